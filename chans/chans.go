@@ -0,0 +1,122 @@
+// Package chans provides generic combinators of channels.
+//
+// All functions take a context and return output channels which are closed
+// when the input channels are closed or the context is done, so that the
+// goroutines started by the functions never leak. The output channels can be
+// used as the sources of par.ForEachChan or the par pipelines.
+package chans
+
+import (
+	"context"
+	"sync"
+)
+
+// send sends v to out unless ctx is done. It returns false if ctx is done.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// OrDone returns a channel receiving the values from in until in is closed or
+// ctx is done. It is useful to range over a channel with cancellation.
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge returns a channel receiving the values from all ins. The output is
+// closed when all ins are closed or ctx is done. The order of the values from
+// different channels is not defined.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func() {
+			defer wg.Done()
+			for v := range OrDone(ctx, in) {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Broadcast returns n channels each receiving all values from in. A value is
+// sent to all outputs before the next value is received from in, so the
+// slowest receiver limits the throughput. The outputs are closed when in is
+// closed or ctx is done.
+func Broadcast[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]chan T, n)
+	recvs := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		recvs[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for v := range OrDone(ctx, in) {
+			for _, out := range outs {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}
+	}()
+	return recvs
+}
+
+// Tee returns two channels each receiving all values from in. It is the same as
+// Broadcast with two outputs.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	outs := Broadcast(ctx, in, 2)
+	return outs[0], outs[1]
+}
+
+// Take returns a channel receiving the first n values from in. The output is
+// closed after n values, or when in is closed or ctx is done. The values after
+// the first n are not received from in.
+func Take[T any](ctx context.Context, in <-chan T, n int) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for i := 0; i < n; i++ {
+			select {
+			case v, ok := <-in:
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
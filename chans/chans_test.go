@@ -0,0 +1,150 @@
+package chans
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jaeyeom/sugo/leaktest"
+	"github.com/jaeyeom/sugo/par"
+)
+
+// generate returns a channel sending from 0 to n-1, or forever if n is
+// negative.
+func generate(ctx context.Context, n int) <-chan int {
+	out := make(chan int)
+	go func() {
+		defer close(out)
+		for i := 0; n < 0 || i < n; i++ {
+			if !send(ctx, out, i) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func collect[T any](in <-chan T) []T {
+	var vs []T
+	for v := range in {
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+func ExampleMerge() {
+	ctx := context.Background()
+	merged := collect(Merge(ctx, generate(ctx, 3), generate(ctx, 2)))
+	slices.Sort(merged)
+	fmt.Println(merged)
+	// Output: [0 0 1 1 2]
+}
+
+func ExampleTake() {
+	ctx, cancel := context.WithCancel(context.Background())
+	// Cancel the infinite generator after taking the values.
+	defer cancel()
+	fmt.Println(collect(Take(ctx, generate(ctx, -1), 5)))
+	// Output: [0 1 2 3 4]
+}
+
+func ExampleTee() {
+	ctx := context.Background()
+	a, b := Tee(ctx, generate(ctx, 3))
+	for v := range a {
+		fmt.Println(v, <-b)
+	}
+	// Output:
+	// 0 0
+	// 1 1
+	// 2 2
+}
+
+// The channels can be the source of par.ForEachChan.
+func ExampleOrDone_forEachChan() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var sum atomic.Int64
+	err := par.ForEachChan(ctx, Take(ctx, generate(ctx, -1), 100), 4, func(_ context.Context, v int) error {
+		sum.Add(int64(v))
+		return nil
+	})
+	fmt.Println(sum.Load(), err)
+	// Output: 4950 <nil>
+}
+
+func TestOrDone_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := OrDone(ctx, generate(ctx, -1))
+	<-out
+	cancel()
+	// The output is closed after the cancellation.
+	for range out {
+	}
+}
+
+func TestMerge_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Merge(ctx, generate(ctx, -1), generate(ctx, -1), generate(ctx, -1))
+	<-out
+	cancel()
+	for range out {
+	}
+}
+
+func TestMerge_empty(t *testing.T) {
+	leaktest.Check(t)
+	if vs := collect(Merge[int](context.Background())); len(vs) != 0 {
+		t.Errorf("Merge() = %v, want empty", vs)
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	leaktest.Check(t)
+	ctx := context.Background()
+	outs := Broadcast(ctx, generate(ctx, 100), 3)
+	results := par.Map(outs, collect[int])
+	want := collect(generate(ctx, 100))
+	for i, got := range results {
+		if !slices.Equal(got, want) {
+			t.Errorf("output %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestBroadcast_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := Broadcast(ctx, generate(ctx, -1), 2)
+	<-outs[0]
+	// Nobody receives from outs[1], but the goroutine returns after the
+	// cancellation.
+	cancel()
+	for range outs[0] {
+	}
+	for range outs[1] {
+	}
+}
+
+func TestTake(t *testing.T) {
+	leaktest.Check(t)
+	// Cancel the generators which are not drained by Take.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, tc := range []struct {
+		size, n int
+		want    []int
+	}{
+		{5, 3, []int{0, 1, 2}},
+		{2, 3, []int{0, 1}},
+		{5, 0, nil},
+	} {
+		if got := collect(Take(ctx, generate(ctx, tc.size), tc.n)); !slices.Equal(got, tc.want) {
+			t.Errorf("Take(%d values, %d) = %v, want %v", tc.size, tc.n, got, tc.want)
+		}
+	}
+}
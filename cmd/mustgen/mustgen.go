@@ -18,6 +18,14 @@ func (ts typeNames) funcName() (name string) {
 	return name
 }
 
+// getName returns the name of the generic function replacing the function.
+func (ts typeNames) getName() string {
+	if len(ts) == 1 {
+		return "Get"
+	}
+	return fmt.Sprintf("Get%d", len(ts))
+}
+
 func (ts typeNames) argList() string {
 	args := make([]string, len(ts))
 	for i, t := range ts {
@@ -89,6 +97,8 @@ var types = []typeNames{
 func main() {
 	for _, ts := range types {
 		fmt.Printf("// %s panics if err is non-nil and returns %s.\n", ts.funcName(), ts.returnTypeList())
+		fmt.Println("//")
+		fmt.Printf("// Deprecated: Use [%s] instead.\n", ts.getName())
 		fmt.Printf("func %s(%s, err error) %s {\n", ts.funcName(), ts.argList(), ts.returnTypeList())
 		fmt.Println("\tCheckErr(err, 2)")
 		fmt.Printf("\treturn %s\n", ts.returnList())
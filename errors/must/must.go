@@ -20,6 +20,15 @@
 // works with both normal error return and try-like must.
 //
 // You may customize error handling more flexible way using HandleErr.
+//
+// The location where the error was captured is dropped by default. Pass
+// KeepLocation() to ReturnErr or HandleErr to keep it, and use Location to get
+// it.
+//
+// Other panics are not handled by must package. Use RecoverAll, or pass
+// RecoverPanics(), to convert any panic into an error of type *PanicError.
+//
+// For structured logging, use LogErrSlog, or SlogHandler with HandleErr.
 package must
 
 import "runtime"
@@ -29,8 +38,8 @@ import "runtime"
 // to write custom must functions.
 func CheckErr(err error, skip int) {
 	if err != nil {
-		_, file, line, _ := runtime.Caller(skip)
-		panic(wrap{err, file, line})
+		pc, file, line, _ := runtime.Caller(skip)
+		panic(wrap{err, file, line, pc, callers(skip)})
 	}
 }
 
@@ -39,7 +48,28 @@ func Nil(err error) {
 	CheckErr(err, 2)
 }
 
+// Get panics if err is non-nil and returns v. It works for any type, for
+// example must.Get(os.Open(name)).
+func Get[T any](v T, err error) T {
+	CheckErr(err, 2)
+	return v
+}
+
+// Get2 panics if err is non-nil and returns v0 and v1.
+func Get2[T0, T1 any](v0 T0, v1 T1, err error) (T0, T1) {
+	CheckErr(err, 2)
+	return v0, v1
+}
+
+// Get3 panics if err is non-nil and returns v0, v1 and v2.
+func Get3[T0, T1, T2 any](v0 T0, v1 T1, v2 T2, err error) (T0, T1, T2) {
+	CheckErr(err, 2)
+	return v0, v1, v2
+}
+
 // Any panics if err is non-nil and returns interface{}.
+//
+// Deprecated: Use [Get] instead, which keeps the type.
 func Any(v0 interface{}, err error) interface{} {
 	CheckErr(err, 2)
 	return v0
@@ -48,252 +78,336 @@ func Any(v0 interface{}, err error) interface{} {
 // Functions below are auto-generated by github.com/jaeyeom/sugo/cmd/mustgen.
 
 // Bool panics if err is non-nil and returns bool.
+//
+// Deprecated: Use [Get] instead.
 func Bool(v0 bool, err error) bool {
 	CheckErr(err, 2)
 	return v0
 }
 
 // String panics if err is non-nil and returns string.
+//
+// Deprecated: Use [Get] instead.
 func String(v0 string, err error) string {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int panics if err is non-nil and returns int.
+//
+// Deprecated: Use [Get] instead.
 func Int(v0 int, err error) int {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int8 panics if err is non-nil and returns int8.
+//
+// Deprecated: Use [Get] instead.
 func Int8(v0 int8, err error) int8 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int16 panics if err is non-nil and returns int16.
+//
+// Deprecated: Use [Get] instead.
 func Int16(v0 int16, err error) int16 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int32 panics if err is non-nil and returns int32.
+//
+// Deprecated: Use [Get] instead.
 func Int32(v0 int32, err error) int32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int64 panics if err is non-nil and returns int64.
+//
+// Deprecated: Use [Get] instead.
 func Int64(v0 int64, err error) int64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint panics if err is non-nil and returns uint.
+//
+// Deprecated: Use [Get] instead.
 func Uint(v0 uint, err error) uint {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint8 panics if err is non-nil and returns uint8.
+//
+// Deprecated: Use [Get] instead.
 func Uint8(v0 uint8, err error) uint8 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint16 panics if err is non-nil and returns uint16.
+//
+// Deprecated: Use [Get] instead.
 func Uint16(v0 uint16, err error) uint16 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint32 panics if err is non-nil and returns uint32.
+//
+// Deprecated: Use [Get] instead.
 func Uint32(v0 uint32, err error) uint32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint64 panics if err is non-nil and returns uint64.
+//
+// Deprecated: Use [Get] instead.
 func Uint64(v0 uint64, err error) uint64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uintptr panics if err is non-nil and returns uintptr.
+//
+// Deprecated: Use [Get] instead.
 func Uintptr(v0 uintptr, err error) uintptr {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Byte panics if err is non-nil and returns byte.
+//
+// Deprecated: Use [Get] instead.
 func Byte(v0 byte, err error) byte {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Rune panics if err is non-nil and returns rune.
+//
+// Deprecated: Use [Get] instead.
 func Rune(v0 rune, err error) rune {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Float32 panics if err is non-nil and returns float32.
+//
+// Deprecated: Use [Get] instead.
 func Float32(v0 float32, err error) float32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Float64 panics if err is non-nil and returns float64.
+//
+// Deprecated: Use [Get] instead.
 func Float64(v0 float64, err error) float64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Complex64 panics if err is non-nil and returns complex64.
+//
+// Deprecated: Use [Get] instead.
 func Complex64(v0 complex64, err error) complex64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Complex128 panics if err is non-nil and returns complex128.
+//
+// Deprecated: Use [Get] instead.
 func Complex128(v0 complex128, err error) complex128 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Bools panics if err is non-nil and returns []bool.
+//
+// Deprecated: Use [Get] instead.
 func Bools(v0 []bool, err error) []bool {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Strings panics if err is non-nil and returns []string.
+//
+// Deprecated: Use [Get] instead.
 func Strings(v0 []string, err error) []string {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Ints panics if err is non-nil and returns []int.
+//
+// Deprecated: Use [Get] instead.
 func Ints(v0 []int, err error) []int {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int8s panics if err is non-nil and returns []int8.
+//
+// Deprecated: Use [Get] instead.
 func Int8s(v0 []int8, err error) []int8 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int16s panics if err is non-nil and returns []int16.
+//
+// Deprecated: Use [Get] instead.
 func Int16s(v0 []int16, err error) []int16 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int32s panics if err is non-nil and returns []int32.
+//
+// Deprecated: Use [Get] instead.
 func Int32s(v0 []int32, err error) []int32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Int64s panics if err is non-nil and returns []int64.
+//
+// Deprecated: Use [Get] instead.
 func Int64s(v0 []int64, err error) []int64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uints panics if err is non-nil and returns []uint.
+//
+// Deprecated: Use [Get] instead.
 func Uints(v0 []uint, err error) []uint {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint8s panics if err is non-nil and returns []uint8.
+//
+// Deprecated: Use [Get] instead.
 func Uint8s(v0 []uint8, err error) []uint8 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint16s panics if err is non-nil and returns []uint16.
+//
+// Deprecated: Use [Get] instead.
 func Uint16s(v0 []uint16, err error) []uint16 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint32s panics if err is non-nil and returns []uint32.
+//
+// Deprecated: Use [Get] instead.
 func Uint32s(v0 []uint32, err error) []uint32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uint64s panics if err is non-nil and returns []uint64.
+//
+// Deprecated: Use [Get] instead.
 func Uint64s(v0 []uint64, err error) []uint64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Uintptrs panics if err is non-nil and returns []uintptr.
+//
+// Deprecated: Use [Get] instead.
 func Uintptrs(v0 []uintptr, err error) []uintptr {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Bytes panics if err is non-nil and returns []byte.
+//
+// Deprecated: Use [Get] instead.
 func Bytes(v0 []byte, err error) []byte {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Runes panics if err is non-nil and returns []rune.
+//
+// Deprecated: Use [Get] instead.
 func Runes(v0 []rune, err error) []rune {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Float32s panics if err is non-nil and returns []float32.
+//
+// Deprecated: Use [Get] instead.
 func Float32s(v0 []float32, err error) []float32 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Float64s panics if err is non-nil and returns []float64.
+//
+// Deprecated: Use [Get] instead.
 func Float64s(v0 []float64, err error) []float64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Complex64s panics if err is non-nil and returns []complex64.
+//
+// Deprecated: Use [Get] instead.
 func Complex64s(v0 []complex64, err error) []complex64 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // Complex128s panics if err is non-nil and returns []complex128.
+//
+// Deprecated: Use [Get] instead.
 func Complex128s(v0 []complex128, err error) []complex128 {
 	CheckErr(err, 2)
 	return v0
 }
 
 // RuneInt panics if err is non-nil and returns (rune, int).
+//
+// Deprecated: Use [Get2] instead.
 func RuneInt(v0 rune, v1 int, err error) (rune, int) {
 	CheckErr(err, 2)
 	return v0, v1
 }
 
 // RuneBoolString panics if err is non-nil and returns (rune, bool, string).
+//
+// Deprecated: Use [Get3] instead.
 func RuneBoolString(v0 rune, v1 bool, v2 string, err error) (rune, bool, string) {
 	CheckErr(err, 2)
 	return v0, v1, v2
 }
 
 // IntBytes panics if err is non-nil and returns (int, []byte).
+//
+// Deprecated: Use [Get2] instead.
 func IntBytes(v0 int, v1 []byte, err error) (int, []byte) {
 	CheckErr(err, 2)
 	return v0, v1
 }
 
 // BytesBool panics if err is non-nil and returns ([]byte, bool).
+//
+// Deprecated: Use [Get2] instead.
 func BytesBool(v0 []byte, v1 bool, err error) ([]byte, bool) {
 	CheckErr(err, 2)
 	return v0, v1
@@ -1,12 +1,19 @@
 package must
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -23,7 +30,7 @@ func repeatHello(num string) (err error) {
 		}
 	}()
 	defer ReturnErr(&err)
-	for i := Int(strconv.Atoi(num)); i > 0; i-- {
+	for i := Get(strconv.Atoi(num)); i > 0; i-- {
 		fmt.Println("hello")
 	}
 	if num == "3" {
@@ -51,12 +58,12 @@ func ExampleReturnErr() {
 		defer ReturnErr(&err)
 
 		// Error handling is simplified here.
-		// From other packages it looks like must.Int(...).
-		i := Int(strconv.Atoi("a"))
+		// From other packages it looks like must.Get(...).
+		i := Get(strconv.Atoi("a"))
 		fmt.Println(i)
 
 		// Or inlined.
-		fmt.Println(Int(strconv.Atoi("b")))
+		fmt.Println(Get(strconv.Atoi("b")))
 
 		// Compare with the following.
 		i, err = strconv.Atoi("c")
@@ -80,7 +87,7 @@ func ExampleReturnErr_multipleRecover() {
 		defer recoverAnyPanic()
 		defer ReturnErr(&err)
 
-		_ = Int(strconv.Atoi("1"))
+		_ = Get(strconv.Atoi("1"))
 		panic(errors.New("created panic"))
 	}()
 	// Output:
@@ -104,6 +111,91 @@ func ExampleAny() {
 	// strconv.Atoi: parsing "a": invalid syntax
 }
 
+func ExampleGet() {
+	err := func() (err error) {
+		defer ReturnErr(&err)
+
+		// Get works with any types, keeping the type of the value.
+		f := Get(os.Open("does-not-exist"))
+		defer f.Close() //nolint:errcheck
+
+		return nil
+	}()
+	fmt.Println(errors.Is(err, os.ErrNotExist))
+	// Output:
+	// true
+}
+
+func ExampleGet2() {
+	err := func() (err error) {
+		defer ReturnErr(&err)
+		rd := strings.NewReader("€")
+		r, size := Get2(rd.ReadRune())
+		fmt.Println(string(r), size)
+		r, size = Get2(rd.ReadRune())
+		fmt.Println(string(r), size)
+		return nil
+	}()
+	fmt.Println(err)
+	// Output:
+	// € 3
+	// EOF
+}
+
+func TestGet_location(t *testing.T) {
+	var got error
+	func() {
+		defer func() {
+			got, _ = recover().(error)
+		}()
+		Get(strconv.Atoi("a"))
+	}()
+	_, file, line, _ := runtime.Caller(0)
+	want := fmt.Sprintf("%s:%d ", filepath.Base(file), line-2)
+	if got == nil || !strings.HasPrefix(got.Error(), want) {
+		t.Errorf("Get() panicked with %v, want prefix %q", got, want)
+	}
+}
+
+// TestDeprecated checks that the deprecated per-type functions still return
+// the values and report the location of their callers.
+func TestDeprecated(t *testing.T) {
+	if got := Int(strconv.Atoi("12")); got != 12 {
+		t.Errorf("Int() = %d, want 12", got)
+	}
+	if got := String(strconv.Unquote(`"a"`)); got != "a" {
+		t.Errorf("String() = %q, want a", got)
+	}
+	if got := Int64(strconv.ParseInt("-3", 10, 64)); got != -3 {
+		t.Errorf("Int64() = %d, want -3", got)
+	}
+	if got := Bytes(io.ReadAll(strings.NewReader("ab"))); string(got) != "ab" {
+		t.Errorf("Bytes() = %q, want ab", got)
+	}
+	if r, size := RuneInt(strings.NewReader("€").ReadRune()); r != '€' || size != 3 {
+		t.Errorf("RuneInt() = %q, %d, want €, 3", r, size)
+	}
+	for _, tc := range []struct {
+		name string
+		f    func()
+	}{
+		{"Int", func() { Int(strconv.Atoi("a")) }},
+		{"Int64", func() { Int64(strconv.ParseInt("a", 10, 64)) }},
+		{"Any", func() { Any(strconv.Atoi("a")) }},
+	} {
+		err := func() (err error) {
+			defer ReturnErr(&err, KeepLocation())
+			tc.f()
+			return nil
+		}()
+		frame, ok := Location(err)
+		// The location is the closure calling the function in the table.
+		if !ok || !strings.Contains(frame.Function, "must.TestDeprecated.func") {
+			t.Errorf("%s: Location() = %v, %v, want the test function", tc.name, frame.Function, ok)
+		}
+	}
+}
+
 func ExampleLogErr() {
 	// This is probably an anti-pattern because error was logged and
 	// returned, handled twice.
@@ -111,7 +203,7 @@ func ExampleLogErr() {
 		defer ReturnErr(&err)
 		// Log error to stderr with file name and line number.
 		defer LogErr(log.Println)
-		i := Int(strconv.Atoi("a"))
+		i := Get(strconv.Atoi("a"))
 		fmt.Printf("i = %d\n", i)
 		return nil
 	}()
@@ -128,7 +220,7 @@ func ExampleHandleErr_wrapError() {
 		defer HandleErr(func(newerr error) {
 			err = fmt.Errorf("error occurred: %v", newerr)
 		})
-		fmt.Println(Int(strconv.Atoi("a")))
+		fmt.Println(Get(strconv.Atoi("a")))
 		return nil
 	}()
 	fmt.Println(err)
@@ -143,7 +235,7 @@ func ExampleHandleErr_justReturnError() {
 		defer HandleErr(func(newerr error) {
 			err = newerr
 		})
-		fmt.Println(Int(strconv.Atoi("a")))
+		fmt.Println(Get(strconv.Atoi("a")))
 		return nil
 	}()
 	fmt.Println(err)
@@ -160,10 +252,10 @@ func ExampleHandleErr_fromGoVersion2Draft() {
 			err = fmt.Errorf("copy %s %s: %v", src, dst, newerr)
 		})
 
-		r := Any(os.Open(src)).(*os.File)
+		r := Get(os.Open(src))
 		defer r.Close() //nolint:errcheck
 
-		w := Any(os.Create(dst)).(*os.File)
+		w := Get(os.Create(dst))
 		// Note that this should be HandleErrNext(), not HandleErr().
 		defer HandleErrNext(func(error) {
 			w.Close() //nolint:errcheck
@@ -172,7 +264,7 @@ func ExampleHandleErr_fromGoVersion2Draft() {
 			}
 		})
 
-		Int64(io.Copy(w, r))
+		Get(io.Copy(w, r))
 		Nil(w.Close())
 		return nil
 	}
@@ -266,7 +358,7 @@ func ExampleHandleErrorf_returnError() {
 func ExampleHandleErrorf_must() {
 	f := func(id int) (err error) {
 		defer HandleErrorf(&err, "error occurred in example %d", id)
-		fmt.Println(Int(strconv.Atoi("a")))
+		fmt.Println(Get(strconv.Atoi("a")))
 		return nil
 	}
 	fmt.Println(f(10))
@@ -278,7 +370,7 @@ func ExampleHandleErrorf_multiple() {
 		defer HandleErrorf(&err, "error occurred in example %d", id)
 		fmt.Println("running well")
 		defer HandleErrorf(&err, "after running well")
-		fmt.Println(Int(strconv.Atoi("a")))
+		fmt.Println(Get(strconv.Atoi("a")))
 		return nil
 	}
 	fmt.Println(f(10))
@@ -286,3 +378,396 @@ func ExampleHandleErrorf_multiple() {
 	// running well
 	// error occurred in example 10: after running well: strconv.Atoi: parsing "a": invalid syntax
 }
+
+// relay mimics a panic value relaying a panic from another goroutine.
+type relay struct {
+	v any
+}
+
+func (r relay) PanicValue() any {
+	return r.v
+}
+
+func TestReturnErr_relayed(t *testing.T) {
+	err := func() (err error) {
+		defer ReturnErr(&err)
+		defer func() {
+			if r := recover(); r != nil {
+				panic(relay{r})
+			}
+		}()
+		Nil(errors.New("relayed"))
+		return nil
+	}()
+	if err == nil || err.Error() != "relayed" {
+		t.Errorf("ReturnErr() = %v, want relayed", err)
+	}
+}
+
+func ExampleKeepLocation() {
+	err := func() (err error) {
+		defer ReturnErr(&err, KeepLocation())
+		Get(os.Open("does-not-exist"))
+		return nil
+	}()
+	// The error is still matched through the location.
+	fmt.Println(errors.Is(err, os.ErrNotExist))
+	if frame, ok := Location(err); ok {
+		fmt.Println(filepath.Base(frame.File), strings.HasSuffix(frame.Function, "ExampleKeepLocation.func1"))
+	}
+	// Output:
+	// true
+	// must_test.go true
+}
+
+func TestLocation(t *testing.T) {
+	var got error
+	func() {
+		defer HandleErr(func(err error) {
+			got = fmt.Errorf("handled: %w", err)
+		}, KeepLocation())
+		Nil(io.EOF)
+	}()
+	_, file, line, _ := runtime.Caller(0)
+	if !errors.Is(got, io.EOF) {
+		t.Errorf("errors.Is(%v, io.EOF) = false, want true", got)
+	}
+	frame, ok := Location(got)
+	if !ok {
+		t.Fatalf("Location(%v) not found", got)
+	}
+	if frame.File != file || frame.Line != line-2 {
+		t.Errorf("Location() = %s:%d, want %s:%d", frame.File, frame.Line, file, line-2)
+	}
+	if want := "must.TestLocation.func1"; !strings.HasSuffix(frame.Function, want) {
+		t.Errorf("Location().Function = %q, want suffix %q", frame.Function, want)
+	}
+}
+
+func TestLocation_notKept(t *testing.T) {
+	err := func() (err error) {
+		defer ReturnErr(&err)
+		Nil(io.EOF)
+		return nil
+	}()
+	if err != io.EOF {
+		t.Errorf("ReturnErr() = %v, want %v", err, io.EOF)
+	}
+	if _, ok := Location(err); ok {
+		t.Errorf("Location(%v) found, want not found", err)
+	}
+}
+
+// stackTracer is implemented by the errors kept by KeepLocation.
+type stackTracer interface {
+	StackTrace() []runtime.Frame
+}
+
+func TestSetStackCapture(t *testing.T) {
+	defer SetStackCapture(SetStackCapture(true))
+	err := func() (err error) {
+		defer ReturnErr(&err, KeepLocation())
+		Nil(io.EOF)
+		return nil
+	}()
+	st, ok := err.(stackTracer)
+	if !ok {
+		t.Fatalf("%T does not implement StackTrace", err)
+	}
+	frames := st.StackTrace()
+	if len(frames) < 2 {
+		t.Fatalf("StackTrace() = %v, want at least 2 frames", frames)
+	}
+	if want := "must.TestSetStackCapture.func1"; !strings.HasSuffix(frames[0].Function, want) {
+		t.Errorf("StackTrace()[0].Function = %q, want suffix %q", frames[0].Function, want)
+	}
+	if want := "must.TestSetStackCapture"; !strings.HasSuffix(frames[1].Function, want) {
+		t.Errorf("StackTrace()[1].Function = %q, want suffix %q", frames[1].Function, want)
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(verbose, err.Error()+"\n") || !strings.Contains(verbose, "must.TestSetStackCapture") {
+		t.Errorf("%%+v = %q, want the error followed by the stack", verbose)
+	}
+	if got := fmt.Sprintf("%v", err); got != err.Error() {
+		t.Errorf("%%v = %q, want %q", got, err.Error())
+	}
+}
+
+func TestSetStackCapture_disabled(t *testing.T) {
+	defer SetStackCapture(SetStackCapture(false))
+	err := func() (err error) {
+		defer ReturnErr(&err, KeepLocation())
+		Nil(io.EOF)
+		return nil
+	}()
+	if frames := err.(stackTracer).StackTrace(); frames != nil {
+		t.Errorf("StackTrace() = %v, want nil", frames)
+	}
+	if got := fmt.Sprintf("%+v", err); got != err.Error() {
+		t.Errorf("%%+v = %q, want %q", got, err.Error())
+	}
+}
+
+func BenchmarkCheckErr(b *testing.B) {
+	for _, enabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("stack=%v", enabled), func(b *testing.B) {
+			defer SetStackCapture(SetStackCapture(enabled))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				func() {
+					defer HandleErr(func(error) {})
+					Nil(io.EOF)
+				}()
+			}
+		})
+	}
+}
+
+func ExampleRecoverAll() {
+	f := func(m map[string]int) (err error) {
+		// RecoverAll runs before HandleErrorf, which wraps the converted
+		// error.
+		defer HandleErrorf(&err, "update")
+		defer RecoverAll(&err)
+		m["a"] = Get(strconv.Atoi("1"))
+		return nil
+	}
+	fmt.Println(f(map[string]int{}))
+	err := f(nil)
+	fmt.Println(err)
+	var perr *PanicError
+	fmt.Println(errors.As(err, &perr))
+	// Output:
+	// <nil>
+	// update: panic: assignment to entry in nil map
+	// true
+}
+
+func TestRecoverAll_mustError(t *testing.T) {
+	err := func() (err error) {
+		defer RecoverAll(&err)
+		Nil(io.EOF)
+		return nil
+	}()
+	if err != io.EOF {
+		t.Errorf("RecoverAll() = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestRecoverPanics(t *testing.T) {
+	var got error
+	func() {
+		defer HandleErr(func(err error) {
+			got = err
+		}, RecoverPanics())
+		var s []int
+		_ = s[1]
+	}()
+	var perr *PanicError
+	if !errors.As(got, &perr) {
+		t.Fatalf("HandleErr() got %v, want *PanicError", got)
+	}
+	var rerr runtime.Error
+	if !errors.As(got, &rerr) {
+		t.Errorf("errors.As(%v, runtime.Error) = false, want true", got)
+	}
+	if !strings.Contains(string(perr.Stack), "must.TestRecoverPanics.func1") {
+		t.Errorf("Stack = %s, want the panicking function", perr.Stack)
+	}
+}
+
+func TestRecoverPanics_notGiven(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v, want boom", r)
+		}
+	}()
+	func() (err error) {
+		defer ReturnErr(&err)
+		panic("boom")
+	}()
+	t.Error("ReturnErr() recovered a panic not captured by must")
+}
+
+// newTestLogger returns a logger writing to w without the time, and with the
+// base name of the file in the location.
+func newTestLogger(w io.Writer, asJSON bool) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			switch {
+			case len(groups) == 0 && a.Key == slog.TimeKey:
+				return slog.Attr{}
+			case len(groups) == 1 && groups[0] == "location" && a.Key == "file":
+				a.Value = slog.StringValue(filepath.Base(a.Value.String()))
+			case len(groups) == 1 && groups[0] == "location" && a.Key == "line":
+				// Drop the line to keep the example output stable.
+				if !asJSON {
+					return slog.Attr{}
+				}
+			}
+			return a
+		},
+	}
+	if asJSON {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+func ExampleLogErrSlog() {
+	logger := newTestLogger(os.Stdout, false)
+	err := func() (err error) {
+		defer ReturnErr(&err)
+		defer LogErrSlog(logger, "parse failed", slog.String("input", "a"))
+		fmt.Println(Get(strconv.Atoi("a")))
+		return nil
+	}()
+	fmt.Println(err)
+	// Output:
+	// level=ERROR msg="parse failed" input=a error="strconv.Atoi: parsing \"a\": invalid syntax" error_chain="[*strconv.NumError *errors.errorString]" location.file=must_test.go location.function=github.com/jaeyeom/sugo/errors/must.ExampleLogErrSlog.func1
+	// strconv.Atoi: parsing "a": invalid syntax
+}
+
+func ExampleSlogHandler() {
+	logger := newTestLogger(os.Stdout, false)
+	err := func() (err error) {
+		defer ReturnErr(&err)
+		// KeepLocation is required for the location in the log. The
+		// handler does not consume the error with HandleErrNext, so that
+		// ReturnErr returns it.
+		defer HandleErrNext(SlogHandler(logger, "parse failed"), KeepLocation())
+		fmt.Println(Get(strconv.Atoi("a")))
+		return nil
+	}()
+	fmt.Println(err)
+	// Output:
+	// level=ERROR msg="parse failed" error="strconv.Atoi: parsing \"a\": invalid syntax" error_chain="[*strconv.NumError *errors.errorString]" location.file=must_test.go location.function=github.com/jaeyeom/sugo/errors/must.ExampleSlogHandler.func1
+	// strconv.Atoi: parsing "a": invalid syntax
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, true)
+	err := func() (err error) {
+		defer HandleErr(SlogHandler(logger, "copy failed"), KeepLocation())
+		Nil(fmt.Errorf("copy: %w", errors.Join(io.EOF, io.ErrClosedPipe)))
+		return nil
+	}()
+	_, _, line, _ := runtime.Caller(0)
+	if err != nil {
+		t.Errorf("HandleErr() returned %v, want nil", err)
+	}
+	var got struct {
+		Msg        string
+		Error      string
+		ErrorChain []string `json:"error_chain"`
+		Location   struct {
+			File     string
+			Line     int
+			Function string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", buf.Bytes(), err)
+	}
+	if got.Msg != "copy failed" {
+		t.Errorf("msg = %q, want %q", got.Msg, "copy failed")
+	}
+	if want := "copy: EOF\nio: read/write on closed pipe"; got.Error != want {
+		t.Errorf("error = %q, want %q", got.Error, want)
+	}
+	if want := []string{"*fmt.wrapError", "*errors.joinError", "*errors.errorString", "*errors.errorString"}; !slices.Equal(got.ErrorChain, want) {
+		t.Errorf("error_chain = %v, want %v", got.ErrorChain, want)
+	}
+	if got.Location.File != "must_test.go" || got.Location.Line != line-3 {
+		t.Errorf("location = %s:%d, want must_test.go:%d", got.Location.File, got.Location.Line, line-3)
+	}
+	if want := "must.TestSlogHandler.func1"; !strings.HasSuffix(got.Location.Function, want) {
+		t.Errorf("location.function = %q, want suffix %q", got.Location.Function, want)
+	}
+}
+
+func TestKeepLocation_comparable(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		func() {
+			defer SetStackCapture(SetStackCapture(enabled))
+			capture := func() (err error) {
+				defer ReturnErr(&err, KeepLocation())
+				Nil(io.EOF)
+				return nil
+			}
+			err1, err2 := capture(), capture()
+			// Comparing the errors should not panic. They are equal unless
+			// they have the stacks captured separately.
+			if got := err1 == err2; got == enabled {
+				t.Errorf("stack=%v: (%v == %v) = %v, want %v", enabled, err1, err2, got, !enabled)
+			}
+			if !errors.Is(err1, io.EOF) {
+				t.Errorf("stack=%v: errors.Is(%v, io.EOF) = false, want true", enabled, err1)
+			}
+		}()
+	}
+}
+
+func TestRecoverAll_optsNotModified(t *testing.T) {
+	// RecoverAll should not write into the spare capacity of opts.
+	opts := make([]Option, 1, 2)
+	opts[0] = KeepLocation()
+	func() (err error) {
+		defer RecoverAll(&err, opts...)
+		panic("boom")
+	}()
+	if extra := opts[:2][1]; extra != nil {
+		t.Error("RecoverAll() appended to opts")
+	}
+}
+
+func TestKeepLocation_format(t *testing.T) {
+	err := func() (err error) {
+		defer ReturnErr(&err, KeepLocation())
+		Nil(io.EOF)
+		return nil
+	}()
+	msg := err.Error()
+	for _, tc := range []struct {
+		format string
+		want   string
+	}{
+		{"%v", msg},
+		{"%s", msg},
+		{"%q", strconv.Quote(msg)},
+		{"%x", fmt.Sprintf("%x", msg)},
+		{"%d", "%!d(string=" + msg + ")"},
+	} {
+		if got := fmt.Sprintf(tc.format, err); got != tc.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+}
+
+func TestSlogHandler_wrapped(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, true)
+	func() {
+		defer HandleErr(func(err error) {
+			SlogHandler(logger, "failed")(fmt.Errorf("loading config: %w", err))
+		}, KeepLocation())
+		Get(strconv.Atoi("x"))
+	}()
+	var got struct {
+		Error    string
+		Location struct {
+			Function string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) failed: %v", buf.Bytes(), err)
+	}
+	// The context of the outer error is kept.
+	if !strings.HasPrefix(got.Error, "loading config: ") || !strings.HasSuffix(got.Error, `strconv.Atoi: parsing "x": invalid syntax`) {
+		t.Errorf("error = %q, want the outer context and the original error", got.Error)
+	}
+	if want := "must.TestSlogHandler_wrapped.func1"; !strings.HasSuffix(got.Location.Function, want) {
+		t.Errorf("location.function = %q, want suffix %q", got.Location.Function, want)
+	}
+}
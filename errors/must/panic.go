@@ -0,0 +1,43 @@
+package must
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error converted from a panic by RecoverAll or the defer
+// functions given RecoverPanics.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+func newPanicError(r any) *PanicError {
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+// Error returns the panic value formatted as a string.
+func (p *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Unwrap returns the panic value if it is an error, such as runtime.Error.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// RecoverAll is a defer function converting any panic into the error pointed
+// by perr. It is the same as ReturnErr(perr, RecoverPanics()). Errors captured
+// by must package are returned as ReturnErr does, and other panics are returned
+// as *PanicError.
+func RecoverAll(perr *error, opts ...Option) {
+	if r := recover(); r != nil {
+		c := newConfig(opts)
+		c.recoverPanics = true
+		err, _ := c.handle(r)
+		*perr = err
+	}
+}
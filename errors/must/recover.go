@@ -1,14 +1,19 @@
 package must
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"runtime"
 )
 
 type wrap struct {
 	err  error
 	file string
 	line int
+	pc   uintptr
+	// stack is behind a pointer to keep wrap comparable.
+	stack *[]uintptr
 }
 
 // Error returns an error string with file and line.
@@ -16,13 +21,111 @@ func (w wrap) Error() string {
 	return fmt.Sprintf("%s:%d %v", filepath.Base(w.file), w.line, w.err)
 }
 
+// Unwrap returns the captured error.
+func (w wrap) Unwrap() error {
+	return w.err
+}
+
+// handle returns the error to pass for the recovered value r. It returns false
+// if r should be re-panicked.
+func (c *config) handle(r any) (error, bool) {
+	if e, ok := asWrap(r); ok {
+		return c.errOf(e), true
+	}
+	if c.recoverPanics {
+		return newPanicError(r), true
+	}
+	return nil, false
+}
+
+// Location returns the location where the error in the chain of err was
+// captured by must package, if the error was kept by KeepLocation. The
+// Function field of the frame is the name of the function calling must.
+func Location(err error) (runtime.Frame, bool) {
+	var w wrap
+	if !errors.As(err, &w) {
+		return runtime.Frame{}, false
+	}
+	frame := runtime.Frame{PC: w.pc, File: w.file, Line: w.line}
+	if fn := runtime.FuncForPC(w.pc); fn != nil {
+		frame.Func = fn
+		frame.Function = fn.Name()
+	}
+	return frame, true
+}
+
+// Option configures how the defer functions handle the captured errors.
+type Option func(*config)
+
+type config struct {
+	keepLocation  bool
+	recoverPanics bool
+}
+
+// KeepLocation makes the defer functions pass the error annotated with the
+// file and line where it was captured, instead of the original error. The
+// annotated error unwraps to the original error, so errors.Is and errors.As
+// still work, and Location returns the location.
+func KeepLocation() Option {
+	return func(c *config) {
+		c.keepLocation = true
+	}
+}
+
+// RecoverPanics makes the defer functions handle any panic as an error of type
+// *PanicError, not only the errors captured by must package.
+func RecoverPanics() Option {
+	return func(c *config) {
+		c.recoverPanics = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// errOf returns the error to pass for the captured error w.
+func (c *config) errOf(w wrap) error {
+	if c.keepLocation {
+		return w
+	}
+	return w.err
+}
+
+// relayed is implemented by panic values relaying a panic from another
+// goroutine, such as *par.Panic.
+type relayed interface {
+	PanicValue() any
+}
+
+// asWrap returns the error captured by must package in the recovered value r.
+// Panics relayed from other goroutines are unwrapped.
+func asWrap(r any) (wrap, bool) {
+	for {
+		switch v := r.(type) {
+		case wrap:
+			return v, true
+		case relayed:
+			r = v.PanicValue()
+		default:
+			return wrap{}, false
+		}
+	}
+}
+
 // ReturnErr is a defer function to simplify returning errors. The pointer to
 // the returning error variable perr should be passed. Errors captured by must
-// package are handled. Other panic values won't be handled here.
-func ReturnErr(perr *error) {
+// package are handled, including the ones relayed from other goroutines by
+// package par. Other panic values won't be handled here unless RecoverPanics is
+// given.
+func ReturnErr(perr *error, opts ...Option) {
 	if r := recover(); r != nil {
-		if e, ok := r.(wrap); ok {
-			*perr = e.err
+		if err, ok := newConfig(opts).handle(r); ok {
+			*perr = err
 		} else {
 			panic(r)
 		}
@@ -32,7 +135,7 @@ func ReturnErr(perr *error) {
 // LogErr is a defer function to simplify logging.
 func LogErr(logger func(...interface{})) {
 	if r := recover(); r != nil {
-		if e, ok := r.(wrap); ok {
+		if e, ok := asWrap(r); ok {
 			logger(e)
 		}
 		panic(r)
@@ -41,10 +144,10 @@ func LogErr(logger func(...interface{})) {
 
 // HandleErr is a defer function to customize error handling. This can be used
 // in case the returning error is custom typed or logging is required.
-func HandleErr(handler func(error)) {
+func HandleErr(handler func(error), opts ...Option) {
 	if r := recover(); r != nil {
-		if e, ok := r.(wrap); ok {
-			handler(e.err)
+		if err, ok := newConfig(opts).handle(r); ok {
+			handler(err)
 		} else {
 			panic(r)
 		}
@@ -55,10 +158,10 @@ func HandleErr(handler func(error)) {
 // error handling. This is similar to HandleErr except the panic is not
 // consumed. Use this to let other deferred handlers above handle the error as
 // well.
-func HandleErrNext(handler func(error)) {
+func HandleErrNext(handler func(error), opts ...Option) {
 	if r := recover(); r != nil {
-		if e, ok := r.(wrap); ok {
-			handler(e.err)
+		if err, ok := newConfig(opts).handle(r); ok {
+			handler(err)
 		}
 		panic(r)
 	}
@@ -70,10 +173,14 @@ func HandleErrNext(handler func(error)) {
 // the deafult behavior of must check is panic. In case you defer HandleErrorf,
 // you don't need to defer ReturnErr.
 //
+// Panics not captured by must package are re-panicked. To wrap them as well,
+// defer RecoverAll(&err) after HandleErrorf, so that RecoverAll converts the
+// panic into the error before HandleErrorf wraps it.
+//
 // Here's the link to Go 2 try proposal: https://github.com/golang/go/issues/32437
 func HandleErrorf(perr *error, format string, args ...interface{}) {
 	if r := recover(); r != nil {
-		if e, ok := r.(wrap); ok {
+		if e, ok := asWrap(r); ok {
 			*perr = e.err
 		} else {
 			panic(r)
@@ -0,0 +1,85 @@
+package must
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// LogErrSlog is a defer function to log the error captured by must package with
+// logger at the error level. The attributes of SlogHandler are added after
+// attrs. The panic is not consumed like LogErr. If logger is nil,
+// slog.Default() is used.
+func LogErrSlog(logger *slog.Logger, msg string, attrs ...slog.Attr) {
+	if r := recover(); r != nil {
+		if e, ok := asWrap(r); ok {
+			logSlog(logger, msg, attrs, e)
+		}
+		panic(r)
+	}
+}
+
+// SlogHandler returns a handler for HandleErr and HandleErrNext logging the
+// error with logger at the error level. The log has the attributes below after
+// attrs.
+//
+//   - error: the message of the error.
+//   - error_chain: the types of the errors in the chain of the error.
+//   - location: a group of file, line and function where the error was
+//     captured. It is present only if KeepLocation is given to HandleErr or
+//     HandleErrNext along with the handler, since the location is dropped by
+//     default.
+//
+// If logger is nil, slog.Default() is used.
+func SlogHandler(logger *slog.Logger, msg string, attrs ...slog.Attr) func(error) {
+	return func(err error) {
+		logSlog(logger, msg, attrs, err)
+	}
+}
+
+func logSlog(logger *slog.Logger, msg string, attrs []slog.Attr, err error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	attrs = attrs[:len(attrs):len(attrs)]
+	if w, ok := err.(wrap); ok {
+		// Drop the location from the message as it is a separate group.
+		// The location inside a wrapped error is kept with the context.
+		attrs = append(attrs, slog.String("error", w.err.Error()))
+	} else {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	attrs = append(attrs, slog.Any("error_chain", errorChain(err)))
+	if frame, ok := Location(err); ok {
+		attrs = append(attrs, slog.Group("location",
+			slog.String("file", frame.File),
+			slog.Int("line", frame.Line),
+			slog.String("function", frame.Function),
+		))
+	}
+	logger.LogAttrs(context.Background(), slog.LevelError, msg, attrs...)
+}
+
+// errorChain returns the types of the errors in the tree of err in pre-order,
+// skipping the errors annotated with the location by must package.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if _, ok := err.(wrap); !ok {
+			chain = append(chain, fmt.Sprintf("%T", err))
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			if next := e.Unwrap(); next != nil {
+				walk(next)
+			}
+		case interface{ Unwrap() []error }:
+			for _, next := range e.Unwrap() {
+				walk(next)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
@@ -0,0 +1,65 @@
+package must
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames captured.
+const maxStackDepth = 64
+
+var captureStack atomic.Bool
+
+// SetStackCapture sets whether the full stack is captured where errors are
+// captured by must package, and returns the previous setting. It is disabled by
+// default to keep capturing errors cheap, unless built with the muststack build
+// tag. The stack is available by the StackTrace method and the %+v format of
+// the error kept by KeepLocation.
+func SetStackCapture(enabled bool) bool {
+	return captureStack.Swap(enabled)
+}
+
+// callers returns the stack from the caller skip frames above the caller of
+// callers, or nil if the stack capture is disabled.
+func callers(skip int) *[]uintptr {
+	if !captureStack.Load() {
+		return nil
+	}
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(skip+2, pcs)]
+	return &pcs
+}
+
+// StackTrace returns the stack where the error was captured, from the function
+// calling must. It returns nil if the stack capture was disabled.
+func (w wrap) StackTrace() []runtime.Frame {
+	if w.stack == nil || len(*w.stack) == 0 {
+		return nil
+	}
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(*w.stack)
+	for {
+		frame, more := it.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// Format formats the error. The %+v format prints the stack trace after the
+// error message if it was captured. Other verbs format the error message as a
+// string.
+func (w wrap) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, w.Error()) //nolint:errcheck
+		for _, frame := range w.StackTrace() {
+			fmt.Fprintf(s, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+		}
+	default:
+		fmt.Fprintf(s, fmt.FormatString(s, verb), w.Error())
+	}
+}
@@ -0,0 +1,7 @@
+//go:build muststack
+
+package must
+
+func init() {
+	SetStackCapture(true)
+}
@@ -0,0 +1,149 @@
+// Package leaktest provides a test helper to detect leaked goroutines.
+//
+// Call Check at the beginning of a test. It takes a snapshot of the running
+// goroutines, and fails the test at the end if new goroutines are still running
+// after a grace period. Since the goroutines of other tests are not
+// distinguished, do not use it in parallel tests.
+package leaktest
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// defaultIgnored is the stack substrings of the goroutines run by the testing
+// package and the runtime, which are not leaks.
+var defaultIgnored = []string{
+	"testing.tRunner(",
+	"testing.(*T).Run(",
+	"testing.(*M).",
+	"testing.runTests(",
+	"os/signal.signal_recv(",
+	"runtime.ensureSigM(",
+}
+
+type checker struct {
+	grace   time.Duration
+	ignored []string
+}
+
+// Option is a checker option.
+type Option func(*checker)
+
+// WithGrace sets how long to wait for the new goroutines to finish. The default
+// is 5 seconds.
+func WithGrace(d time.Duration) Option {
+	return func(c *checker) {
+		c.grace = d
+	}
+}
+
+// Ignore ignores the goroutines whose stack contains substr, like a function
+// name of a background goroutine which is expected to keep running.
+func Ignore(substr string) Option {
+	return func(c *checker) {
+		c.ignored = append(c.ignored, substr)
+	}
+}
+
+// Check takes a snapshot of the running goroutines and registers a cleanup on t
+// which fails the test with the stacks of the new goroutines if they are still
+// running after the grace period.
+func Check(t testing.TB, opts ...Option) {
+	t.Helper()
+	c := &checker{grace: 5 * time.Second, ignored: defaultIgnored}
+	for _, opt := range opts {
+		opt(c)
+	}
+	before := make(map[int]bool)
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+	t.Cleanup(func() {
+		deadline := time.Now().Add(c.grace)
+		for {
+			leaked := c.leaked(before)
+			if len(leaked) == 0 {
+				return
+			}
+			if time.Now().After(deadline) {
+				stacks := make([]string, len(leaked))
+				for i, g := range leaked {
+					stacks[i] = g.stack
+				}
+				t.Errorf("leaktest: %d goroutine(s) leaked:\n\n%s", len(leaked), strings.Join(stacks, "\n\n"))
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+// leaked returns the goroutines which are not in before nor ignored.
+func (c *checker) leaked(before map[int]bool) []goroutine {
+	var leaked []goroutine
+	for _, g := range goroutines() {
+		if before[g.id] || c.isIgnored(g) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+func (c *checker) isIgnored(g goroutine) bool {
+	for _, s := range c.ignored {
+		if strings.Contains(g.stack, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// goroutine is a running goroutine with its stack.
+type goroutine struct {
+	id    int
+	stack string
+}
+
+// goroutines returns all goroutines except the current one.
+func goroutines() []goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	stacks := bytes.Split(buf, []byte("\n\n"))
+	gs := make([]goroutine, 0, len(stacks))
+	// The first stack is the current goroutine.
+	for _, stack := range stacks[1:] {
+		g, ok := parse(string(stack))
+		if ok {
+			gs = append(gs, g)
+		}
+	}
+	return gs
+}
+
+// parse parses a goroutine stack starting with "goroutine N [state]:".
+func parse(stack string) (goroutine, bool) {
+	header, _, _ := strings.Cut(stack, "\n")
+	rest, ok := strings.CutPrefix(header, "goroutine ")
+	if !ok {
+		return goroutine{}, false
+	}
+	idStr, _, _ := strings.Cut(rest, " ")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return goroutine{}, false
+	}
+	return goroutine{id: id, stack: stack}, true
+}
@@ -0,0 +1,81 @@
+package leaktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeT records the errors and runs the cleanups on finish.
+type fakeT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) finish() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func leakUntil(done <-chan struct{}) {
+	<-done
+}
+
+func TestCheck_leak(t *testing.T) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	defer close(done)
+	Check(ft, WithGrace(50*time.Millisecond))
+	go leakUntil(done)
+	ft.finish()
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "leakUntil") {
+		t.Errorf("errors = %q, want a leak of leakUntil", ft.errors)
+	}
+}
+
+func TestCheck_finishedInGrace(t *testing.T) {
+	ft := &fakeT{}
+	Check(ft)
+	go time.Sleep(50 * time.Millisecond)
+	ft.finish()
+	if len(ft.errors) != 0 {
+		t.Errorf("errors = %q, want none", ft.errors)
+	}
+}
+
+func TestCheck_ignore(t *testing.T) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	defer close(done)
+	Check(ft, WithGrace(10*time.Millisecond), Ignore("leakUntil"))
+	go leakUntil(done)
+	ft.finish()
+	if len(ft.errors) != 0 {
+		t.Errorf("errors = %q, want none", ft.errors)
+	}
+}
+
+func TestCheck_existing(t *testing.T) {
+	ft := &fakeT{}
+	done := make(chan struct{})
+	defer close(done)
+	go leakUntil(done)
+	Check(ft, WithGrace(10*time.Millisecond))
+	ft.finish()
+	if len(ft.errors) != 0 {
+		t.Errorf("errors = %q, want none", ft.errors)
+	}
+}
@@ -0,0 +1,164 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jaeyeom/sugo/defergroup"
+)
+
+// ErrBatchClosed is returned by Batch.Add after the batch is closed.
+var ErrBatchClosed = errors.New("par: batch is closed")
+
+// Batch collects items added concurrently and flushes them in groups. A group
+// is flushed when it reaches the max size or the max delay passes since its
+// first item was added. Flushes run concurrently up to the limit set by
+// WithLimit, which is 1 by default. When all flushers are busy, Add blocks.
+type Batch[T any] struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	maxSize  int
+	maxDelay time.Duration
+	clock    Clock
+	items    chan T
+	batches  chan []T
+
+	mu     sync.RWMutex
+	closed bool
+
+	done    chan struct{}
+	errMu   sync.Mutex
+	errs    []error
+	recover any
+}
+
+// NewBatch returns a new batch calling flush for each group of at most maxSize
+// items. If maxDelay is positive, the pending items are flushed after maxDelay
+// even if the group is not full. The ctx is passed to flush, and the batch
+// stops when ctx is done. Errors returned by flush do not stop the batch and
+// are reported by Close. The options WithLimit and WithClock are used, and
+// WithRate and WithObserver apply to each flush.
+func NewBatch[T any](ctx context.Context, maxSize int, maxDelay time.Duration, flush func(ctx context.Context, items []T) error, opts ...Option) *Batch[T] {
+	c := newConfig(opts)
+	ctx, cancel := context.WithCancel(ctx)
+	b := &Batch[T]{
+		ctx:      ctx,
+		cancel:   cancel,
+		maxSize:  max(maxSize, 1),
+		maxDelay: maxDelay,
+		clock:    c.clock,
+		items:    make(chan T),
+		batches:  make(chan []T),
+		done:     make(chan struct{}),
+	}
+	go b.collect()
+	go func() {
+		defer close(b.done)
+		defer cancel()
+		defer func() {
+			b.recover = recover()
+		}()
+		err := ForEachChan(ctx, b.batches, max(c.limit, 1), func(ctx context.Context, items []T) error {
+			if err := flush(ctx, items); err != nil {
+				b.errMu.Lock()
+				defer b.errMu.Unlock()
+				b.errs = append(b.errs, err)
+			}
+			return nil
+		}, opts...)
+		if err != nil {
+			b.errMu.Lock()
+			defer b.errMu.Unlock()
+			b.errs = append(b.errs, err)
+		}
+	}()
+	return b
+}
+
+// collect groups the items and sends the groups to the flushers.
+func (b *Batch[T]) collect() {
+	defer close(b.batches)
+	var (
+		buf   []T
+		timer <-chan time.Time
+	)
+	send := func() bool {
+		if len(buf) == 0 {
+			return true
+		}
+		select {
+		case b.batches <- buf:
+			buf, timer = nil, nil
+			return true
+		case <-b.ctx.Done():
+			return false
+		}
+	}
+	for {
+		select {
+		case v, ok := <-b.items:
+			if !ok {
+				send()
+				return
+			}
+			if len(buf) == 0 && b.maxDelay > 0 {
+				timer = b.clock.After(b.maxDelay)
+			}
+			buf = append(buf, v)
+			if len(buf) >= b.maxSize && !send() {
+				return
+			}
+		case <-timer:
+			if !send() {
+				return
+			}
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// Add adds an item to the batch. It returns ErrBatchClosed if the batch is
+// closed, or the context error if the batch is stopped.
+func (b *Batch[T]) Add(v T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrBatchClosed
+	}
+	select {
+	case b.items <- v:
+		return nil
+	case <-b.ctx.Done():
+		return context.Cause(b.ctx)
+	}
+}
+
+// Close flushes the pending items, waits for all flushes to finish and returns
+// the errors from the flushes joined by errors.Join. If a flush panicked, the
+// panic is re-panicked as *Panic. It is safe to call Close multiple times.
+func (b *Batch[T]) Close() error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.items)
+	}
+	b.mu.Unlock()
+	<-b.done
+	if b.recover != nil {
+		panic(b.recover)
+	}
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	return errors.Join(b.errs...)
+}
+
+// DeferClose adds Close of the batch to the deferred functions of g. The error
+// from Close is joined into *perr, which is usually the named return error.
+func (b *Batch[T]) DeferClose(g *defergroup.Group, perr *error) {
+	g.Defer(func() {
+		*perr = errors.Join(*perr, b.Close())
+	})
+}
@@ -0,0 +1,135 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jaeyeom/sugo/defergroup"
+	"github.com/jaeyeom/sugo/leaktest"
+)
+
+func ExampleBatch() {
+	var (
+		mu      sync.Mutex
+		written []int
+		flushes int
+	)
+	write := func(_ context.Context, items []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, items...)
+		flushes++
+		return nil
+	}
+	err := func() (err error) {
+		g := defergroup.New()
+		defer g.Done()
+		b := NewBatch(context.Background(), 10, time.Second, write, WithLimit(2))
+		b.DeferClose(g, &err)
+		For(100, func(i int) {
+			_ = b.Add(i)
+		}, WithLimit(4))
+		return nil
+	}()
+	slices.Sort(written)
+	fmt.Println(len(written), written[0], written[99], flushes >= 10, err)
+	// Output: 100 0 99 true <nil>
+}
+
+func TestBatch_maxSize(t *testing.T) {
+	leaktest.Check(t)
+	var sizes []int
+	b := NewBatch(context.Background(), 3, 0, func(_ context.Context, items []int) error {
+		sizes = append(sizes, len(items))
+		return nil
+	})
+	for i := 0; i < 10; i++ {
+		if err := b.Add(i); err != nil {
+			t.Fatalf("Add() = %v", err)
+		}
+	}
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+	if want := []int{3, 3, 3, 1}; !slices.Equal(sizes, want) {
+		t.Errorf("flushed sizes %v, want %v", sizes, want)
+	}
+	if err := b.Add(10); !errors.Is(err, ErrBatchClosed) {
+		t.Errorf("Add() after Close = %v, want %v", err, ErrBatchClosed)
+	}
+	if err := b.Close(); err != nil {
+		t.Errorf("second Close() = %v", err)
+	}
+}
+
+func TestBatch_maxDelay(t *testing.T) {
+	flushed := make(chan []int, 1)
+	b := NewBatch(context.Background(), 100, 10*time.Millisecond, func(_ context.Context, items []int) error {
+		flushed <- items
+		return nil
+	})
+	defer b.Close() //nolint:errcheck
+	_ = b.Add(1)
+	_ = b.Add(2)
+	select {
+	case items := <-flushed:
+		if !slices.Equal(items, []int{1, 2}) {
+			t.Errorf("flushed %v, want [1 2]", items)
+		}
+	case <-time.After(time.Second):
+		t.Error("items are not flushed after the max delay")
+	}
+}
+
+func TestBatch_errors(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	var calls int
+	b := NewBatch(context.Background(), 2, 0, func(context.Context, []int) error {
+		calls++
+		return fmt.Errorf("flush %d: %w", calls, errFail)
+	})
+	for i := 0; i < 4; i++ {
+		if err := b.Add(i); err != nil {
+			t.Fatalf("Add() = %v", err)
+		}
+	}
+	err := b.Close()
+	if !errors.Is(err, errFail) || err.Error() != "flush 1: fail\nflush 2: fail" {
+		t.Errorf("Close() = %q, want both flush errors", err)
+	}
+}
+
+func TestBatch_panic(t *testing.T) {
+	leaktest.Check(t)
+	b := NewBatch(context.Background(), 1, 0, func(context.Context, []int) error {
+		panic("three")
+	})
+	// Add does not block after the flusher panicked.
+	for i := 0; i < 3; i++ {
+		_ = b.Add(i)
+	}
+	defer expectPanic(t, "three")
+	_ = b.Close()
+	t.Error("Close should panic")
+}
+
+func TestBatch_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	b := NewBatch(ctx, 10, 0, func(context.Context, []int) error {
+		return nil
+	})
+	cancel()
+	if err := b.Close(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Close() = %v, want %v", err, context.Canceled)
+	}
+	if err := b.Add(1); err == nil {
+		t.Error("Add() after cancel should fail")
+	}
+}
@@ -0,0 +1,119 @@
+package par
+
+import (
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// Executor runs the tasks of the functions in this package. Implementations
+// decide which goroutines run the tasks in which order.
+type Executor interface {
+	// Execute calls task(i) for every i from 0 to n-1 and returns after all
+	// calls return. The task never panics.
+	Execute(n int, task func(i int))
+}
+
+// WithExecutor sets the executor to run the tasks. It takes precedence over the
+// executor chosen by WithLimit, though WithLimit is still used to decide the
+// number of chunks by the functions like ForRange.
+func WithExecutor(e Executor) Option {
+	return func(c *config) {
+		c.executor = e
+	}
+}
+
+// Goroutines returns an executor which calls each task in its own goroutine.
+// It is the default executor.
+func Goroutines() Executor {
+	return goroutines{}
+}
+
+type goroutines struct{}
+
+func (goroutines) Execute(n int, task func(i int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer wg.Done()
+			task(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Pool returns an executor which calls the tasks in a fixed pool of workers
+// goroutines. Each goroutine takes the next task in order of the indices. If
+// workers is not positive, it is the same as Goroutines.
+func Pool(workers int) Executor {
+	if workers <= 0 {
+		return goroutines{}
+	}
+	return pool(workers)
+}
+
+type pool int
+
+func (p pool) Execute(n int, task func(i int)) {
+	workers := int(p)
+	if workers >= n {
+		goroutines{}.Execute(n, task)
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				task(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Sequential returns an executor which calls the tasks one by one in the
+// calling goroutine in order of the indices. It is useful to get reproducible
+// results in tests. Note that tasks waiting for each other, like pipeline
+// stages communicating through channels, deadlock with this executor.
+func Sequential() Executor {
+	return sequential{}
+}
+
+type sequential struct{}
+
+func (sequential) Execute(n int, task func(i int)) {
+	for i := 0; i < n; i++ {
+		task(i)
+	}
+}
+
+// Shuffled returns an executor which calls the tasks one by one in the calling
+// goroutine in a random order generated from seed. It is useful to shake out
+// bugs depending on the order of the tasks in a reproducible way. The same
+// executor generates a different order for each call, but the sequence of the
+// orders is the same for the same seed. It has the same caveat as Sequential.
+func Shuffled(seed uint64) Executor {
+	return &shuffled{rnd: rand.New(rand.NewPCG(seed, seed))}
+}
+
+type shuffled struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func (s *shuffled) Execute(n int, task func(i int)) {
+	s.mu.Lock()
+	order := s.rnd.Perm(n)
+	s.mu.Unlock()
+	for _, i := range order {
+		task(i)
+	}
+}
@@ -0,0 +1,70 @@
+package par
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func ExampleSequential() {
+	var order []int
+	// No synchronization is needed since all calls run in this goroutine.
+	For(5, func(i int) {
+		order = append(order, i)
+	}, WithExecutor(Sequential()))
+	fmt.Println(order)
+	// Output: [0 1 2 3 4]
+}
+
+func ExampleNew_sequential() {
+	New(WithExecutor(Sequential())).Do(
+		func() { fmt.Println("first") },
+		func() { fmt.Println("second") },
+	)
+	// Output:
+	// first
+	// second
+}
+
+func shuffledOrders(seed uint64) [][]int {
+	e := Shuffled(seed)
+	var orders [][]int
+	for n := 0; n < 5; n++ {
+		var order []int
+		For(10, func(i int) {
+			order = append(order, i)
+		}, WithExecutor(e))
+		orders = append(orders, order)
+	}
+	return orders
+}
+
+func TestShuffled(t *testing.T) {
+	orders := shuffledOrders(42)
+	for _, order := range orders {
+		sorted := slices.Sorted(slices.Values(order))
+		if !slices.Equal(sorted, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+			t.Errorf("order %v is not a permutation", order)
+		}
+	}
+	if slices.EqualFunc(orders[:len(orders)-1], orders[1:], slices.Equal) {
+		t.Errorf("orders %v should not be all the same", orders)
+	}
+	if again := shuffledOrders(42); !slices.EqualFunc(orders, again, slices.Equal) {
+		t.Errorf("orders %v and %v should be the same for the same seed", orders, again)
+	}
+}
+
+func TestSequential_panic(t *testing.T) {
+	var calls []int
+	defer func() {
+		if !slices.Equal(calls, []int{0, 1, 2, 3}) {
+			t.Errorf("calls = %v, want [0 1 2 3]", calls)
+		}
+	}()
+	defer expectPanic(t, "three")
+	For(10, func(i int) {
+		calls = append(calls, i)
+		panicAtThree(i)
+	}, WithExecutor(Sequential()))
+}
@@ -0,0 +1,124 @@
+package par
+
+import (
+	"context"
+	"iter"
+	"runtime"
+)
+
+// ForEach calls f(ctx, v) for each value v from seq in workers goroutines and
+// blocks until all calls finish. The seq is iterated in the calling goroutine
+// and waits for an idle worker, so that it is not consumed faster than the
+// values are processed. If workers is not positive, runtime.GOMAXPROCS(0) is
+// used.
+//
+// On the first error, the context passed to f is canceled, the iteration stops
+// and the error is returned in the same way as ForErr. If ctx is done before
+// the iteration finishes, the context error is returned. Panics in f are
+// re-panicked in the calling goroutine as *Panic.
+func ForEach[T any](ctx context.Context, seq iter.Seq[T], workers int, f func(ctx context.Context, v T) error, opts ...Option) error {
+	return forEach(ctx, func(context.Context) iter.Seq[T] {
+		return seq
+	}, workers, f, opts...)
+}
+
+// forEach is the same as ForEach except that the sequence is made by seqOf with
+// a context which is canceled when the workers finish, so that a sequence
+// blocked on a channel can stop when the workers fail.
+func forEach[T any](ctx context.Context, seqOf func(stop context.Context) iter.Seq[T], workers int, f func(ctx context.Context, v T) error, opts ...Option) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	c := newConfig(opts)
+	// The rate limit and the observer apply to the values rather than the
+	// workers.
+	wc := *c
+	wc.limiter, wc.observer = nil, nil
+	type indexed struct {
+		i int
+		v T
+	}
+	values := make(chan indexed)
+	done := make(chan struct{})
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	var (
+		err error
+		p   any
+	)
+	go func() {
+		defer close(done)
+		defer stop()
+		defer func() {
+			p = recover()
+		}()
+		err = wc.runErr(ctx, workers, func(ctx context.Context, _ int) error {
+			for {
+				select {
+				case iv, ok := <-values:
+					if !ok {
+						return nil
+					}
+					var err error
+					if c.observer != nil {
+						err = c.observe(iv.i, func() error {
+							return f(ctx, iv.v)
+						})
+					} else {
+						err = f(ctx, iv.v)
+					}
+					if err != nil {
+						return err
+					}
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		})
+	}()
+	func() {
+		defer close(values)
+		var i int
+		for v := range seqOf(stopCtx) {
+			if c.wait(ctx) != nil {
+				return
+			}
+			select {
+			case values <- indexed{i, v}:
+				i++
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	<-done
+	if p != nil {
+		panic(p)
+	}
+	if err != nil {
+		return err
+	}
+	return context.Cause(ctx)
+}
+
+// ForEachChan is the same as ForEach except that the values are received from
+// ch until it is closed. The receiving stops when a worker fails even if ch is
+// idle.
+func ForEachChan[T any](ctx context.Context, ch <-chan T, workers int, f func(ctx context.Context, v T) error, opts ...Option) error {
+	return forEach(ctx, func(stop context.Context) iter.Seq[T] {
+		return func(yield func(T) bool) {
+			for {
+				select {
+				case v, ok := <-ch:
+					if !ok || !yield(v) {
+						return
+					}
+				case <-stop.Done():
+					return
+				}
+			}
+		}
+	}, workers, f, opts...)
+}
@@ -0,0 +1,142 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jaeyeom/sugo/leaktest"
+)
+
+func ExampleForEach() {
+	lengths := map[string]int{"apple": 5, "banana": 6, "cherry": 6}
+	var total atomic.Int64
+	err := ForEach(context.Background(), maps.Keys(lengths), 2, func(_ context.Context, k string) error {
+		total.Add(int64(len(k)))
+		return nil
+	})
+	fmt.Println(total.Load(), err)
+	// Output: 17 <nil>
+}
+
+func ExampleForEachChan() {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= 100; i++ {
+			ch <- i
+		}
+	}()
+	var total atomic.Int64
+	err := ForEachChan(context.Background(), ch, 4, func(_ context.Context, v int) error {
+		total.Add(int64(v))
+		return nil
+	})
+	fmt.Println(total.Load(), err)
+	// Output: 5050 <nil>
+}
+
+func TestForEach(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithExecutor(Sequential())}, {WithLimit(1)}} {
+		visited := make([]atomic.Int32, 1000)
+		indices := make([]int, len(visited))
+		for i := range indices {
+			indices[i] = i
+		}
+		err := ForEach(context.Background(), slices.Values(indices), 0, func(_ context.Context, v int) error {
+			visited[v].Add(1)
+			return nil
+		}, opts...)
+		if err != nil {
+			t.Errorf("ForEach() = %v", err)
+		}
+		checkVisited(t, "", visited, once)
+	}
+}
+
+// naturals yields the natural numbers until stopped.
+func naturals(yield func(int) bool) {
+	for i := 0; yield(i); i++ {
+	}
+}
+
+func TestForEach_errorStopsIteration(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	err := ForEach(context.Background(), naturals, 3, func(_ context.Context, v int) error {
+		if v == 100 {
+			return errFail
+		}
+		return nil
+	})
+	if !errors.Is(err, errFail) {
+		t.Errorf("ForEach() = %v, want %v", err, errFail)
+	}
+}
+
+func TestForEach_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := ForEach(ctx, naturals, 3, func(_ context.Context, v int) error {
+		if v == 100 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEach() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestForEach_panic(t *testing.T) {
+	leaktest.Check(t)
+	defer expectPanic(t, "three")
+	_ = ForEach(context.Background(), naturals, 2, func(_ context.Context, v int) error {
+		panicAtThree(v)
+		return nil
+	})
+	t.Error("ForEach should panic")
+}
+
+func TestForEachChan_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Nothing is sent to the channel, but ForEachChan should return.
+	err := ForEachChan(ctx, make(chan int), 2, func(context.Context, int) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForEachChan() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestForEachChan_errorOnIdleChannel(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	// The channel is never closed and idle after the first value, but
+	// ForEachChan should return the error of f.
+	ch := make(chan int, 1)
+	ch <- 1
+	err := ForEachChan(context.Background(), ch, 2, func(context.Context, int) error {
+		return errFail
+	})
+	if !errors.Is(err, errFail) {
+		t.Errorf("ForEachChan() = %v, want %v", err, errFail)
+	}
+}
+
+func TestForEachChan_panicOnIdleChannel(t *testing.T) {
+	leaktest.Check(t)
+	defer expectPanic(t, "three")
+	ch := make(chan int, 1)
+	ch <- 3
+	_ = ForEachChan(context.Background(), ch, 2, func(_ context.Context, v int) error {
+		panicAtThree(v)
+		return nil
+	})
+}
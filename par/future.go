@@ -0,0 +1,156 @@
+package par
+
+import (
+	"context"
+	"errors"
+)
+
+// Future is a value computed in a goroutine started by Go, which can be
+// collected later.
+type Future[T any] struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	v      T
+	err    error
+}
+
+// Go calls f in a new goroutine and returns the future of the result. The
+// context passed to f is canceled by Cancel or when f returns. If f panics, the
+// panic is captured as a *Panic error of the future.
+func Go[T any](ctx context.Context, f func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	fu := &Future[T]{done: make(chan struct{}), cancel: cancel}
+	go func() {
+		defer close(fu.done)
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				fu.err = newPanic(r)
+			}
+		}()
+		fu.v, fu.err = f(ctx)
+	}()
+	return fu
+}
+
+// Done returns a channel which is closed when the result is ready.
+func (fu *Future[T]) Done() <-chan struct{} {
+	return fu.done
+}
+
+// Wait blocks until the result is ready and returns it.
+func (fu *Future[T]) Wait() (T, error) {
+	<-fu.done
+	return fu.v, fu.err
+}
+
+// WaitContext is the same as Wait except that it returns the context error if
+// ctx is done before the result is ready. The computation is not canceled.
+func (fu *Future[T]) WaitContext(ctx context.Context) (T, error) {
+	select {
+	case <-fu.done:
+		return fu.v, fu.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Cancel cancels the context passed to the function computing the result. It
+// does not wait for the function to return.
+func (fu *Future[T]) Cancel() {
+	fu.cancel()
+}
+
+// completions returns a channel receiving the index of each future as soon as
+// it is done. The goroutines waiting for the futures return when ctx is done.
+func completions[T any](ctx context.Context, fs []*Future[T]) <-chan int {
+	ch := make(chan int, len(fs))
+	for i, fu := range fs {
+		go func() {
+			select {
+			case <-fu.done:
+				ch <- i
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ch
+}
+
+// cancelAll cancels all the futures.
+func cancelAll[T any](fs []*Future[T]) {
+	for _, fu := range fs {
+		fu.Cancel()
+	}
+}
+
+// All returns a future of all the results in the same order as fs. On the first
+// error, the other futures are canceled and the error is the result. Canceling
+// the returned future cancels all futures.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	return Go(context.Background(), func(ctx context.Context) ([]T, error) {
+		done := completions(ctx, fs)
+		for range fs {
+			select {
+			case i := <-done:
+				if _, err := fs[i].Wait(); err != nil {
+					cancelAll(fs)
+					return nil, err
+				}
+			case <-ctx.Done():
+				cancelAll(fs)
+				return nil, ctx.Err()
+			}
+		}
+		vs := make([]T, len(fs))
+		for i, fu := range fs {
+			vs[i], _ = fu.Wait()
+		}
+		return vs, nil
+	})
+}
+
+// Any returns a future of the first successful result, and the other futures
+// are canceled. If all futures fail, the errors are joined in the same order as
+// fs. Canceling the returned future cancels all futures. If fs is empty, the
+// result is the zero value without an error.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		defer cancelAll(fs)
+		done := completions(ctx, fs)
+		for range fs {
+			select {
+			case i := <-done:
+				if v, err := fs[i].Wait(); err == nil {
+					return v, nil
+				}
+			case <-ctx.Done():
+				var zero T
+				return zero, ctx.Err()
+			}
+		}
+		errs := make([]error, len(fs))
+		for i, fu := range fs {
+			_, errs[i] = fu.Wait()
+		}
+		var zero T
+		return zero, errors.Join(errs...)
+	})
+}
+
+// First returns a future of the first result, either successful or not, and
+// the other futures are canceled. Canceling the returned future cancels all
+// futures. If fs is empty, the result is never ready until canceled.
+func First[T any](fs ...*Future[T]) *Future[T] {
+	return Go(context.Background(), func(ctx context.Context) (T, error) {
+		defer cancelAll(fs)
+		select {
+		case i := <-completions(ctx, fs):
+			return fs[i].Wait()
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	})
+}
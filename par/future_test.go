@@ -0,0 +1,127 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jaeyeom/sugo/leaktest"
+)
+
+func ExampleGo() {
+	fu := Go(context.Background(), func(context.Context) (int, error) {
+		return 42, nil
+	})
+	// Do something else here.
+	fmt.Println(fu.Wait())
+	// Output: 42 <nil>
+}
+
+func ExampleAll() {
+	square := func(n int) *Future[int] {
+		return Go(context.Background(), func(context.Context) (int, error) {
+			return n * n, nil
+		})
+	}
+	fmt.Println(All(square(1), square(2), square(3)).Wait())
+	// Output: [1 4 9] <nil>
+}
+
+// blockUntilCanceled returns a future which returns the context error after it
+// is canceled.
+func blockUntilCanceled() *Future[int] {
+	return Go(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+}
+
+func value(v int, err error) *Future[int] {
+	return Go(context.Background(), func(context.Context) (int, error) {
+		return v, err
+	})
+}
+
+func TestGo_panic(t *testing.T) {
+	_, err := Go(context.Background(), func(context.Context) (int, error) {
+		panic("three")
+	}).Wait()
+	var p *Panic
+	if !errors.As(err, &p) || p.Value != "three" {
+		t.Errorf("Wait() = %v, want *Panic with three", err)
+	}
+}
+
+func TestFuture_WaitContext(t *testing.T) {
+	leaktest.Check(t)
+	fu := blockUntilCanceled()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := fu.WaitContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitContext() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case <-fu.Done():
+		t.Error("future should not be done")
+	default:
+	}
+	fu.Cancel()
+	if _, err := fu.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestAll_error(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	loser := blockUntilCanceled()
+	if _, err := All(loser, value(0, errFail)).Wait(); !errors.Is(err, errFail) {
+		t.Errorf("All() = %v, want %v", err, errFail)
+	}
+	if _, err := loser.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("loser returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestAny(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	loser := blockUntilCanceled()
+	if v, err := Any(value(0, errFail), loser, value(3, nil)).Wait(); v != 3 || err != nil {
+		t.Errorf("Any() = %v, %v, want 3, nil", v, err)
+	}
+	if _, err := loser.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("loser returned %v, want %v", err, context.Canceled)
+	}
+	errOther := errors.New("other")
+	if _, err := Any(value(0, errFail), value(0, errOther)).Wait(); !errors.Is(err, errFail) || !errors.Is(err, errOther) {
+		t.Errorf("Any() = %v, want both %v and %v", err, errFail, errOther)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	leaktest.Check(t)
+	errFail := errors.New("fail")
+	loser := blockUntilCanceled()
+	if _, err := First(loser, value(0, errFail)).Wait(); !errors.Is(err, errFail) {
+		t.Errorf("First() = %v, want %v", err, errFail)
+	}
+	if _, err := loser.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("loser returned %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFirst_cancel(t *testing.T) {
+	leaktest.Check(t)
+	loser := blockUntilCanceled()
+	fu := First(loser)
+	fu.Cancel()
+	if _, err := fu.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("First() = %v, want %v", err, context.Canceled)
+	}
+	if _, err := loser.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("loser returned %v, want %v", err, context.Canceled)
+	}
+}
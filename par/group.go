@@ -0,0 +1,140 @@
+package par
+
+import (
+	"sync"
+	"time"
+)
+
+// Group deduplicates concurrent calls with the same key, so that only one call
+// runs and the others wait for its result. It is useful to collapse the same
+// lookups from the goroutines of the functions like For. Successful results
+// can be cached for TTL. The expired results are evicted as new results are
+// cached, so the cache holds at most about twice the results cached within
+// TTL. The zero value is ready to use, and a Group must not be copied after
+// first use.
+type Group[K comparable, V any] struct {
+	// TTL is how long a successful result is cached. If it is not
+	// positive, the results are not cached.
+	TTL time.Duration
+	// Clock is the clock for TTL. If it is nil, the system clock is used.
+	Clock Clock
+
+	mu    sync.Mutex
+	calls map[K]*call[V]
+	cache map[K]cached[V]
+	// sweepAt is the size of the cache at which the expired results are
+	// evicted.
+	sweepAt int
+}
+
+// minSweep is the minimum size of the cache to evict the expired results.
+const minSweep = 64
+
+// call is an in-flight or completed call of Group.Do.
+type call[V any] struct {
+	done      chan struct{}
+	v         V
+	err       error
+	panic     *Panic
+	forgotten bool
+}
+
+// cached is a cached result of Group.Do.
+type cached[V any] struct {
+	v       V
+	expires time.Time
+}
+
+func (g *Group[K, V]) now() time.Time {
+	if g.Clock == nil {
+		return systemClock{}.Now()
+	}
+	return g.Clock.Now()
+}
+
+// Do calls fn and returns its result, unless the result of the key is cached or
+// another call with the same key is in flight, in which case it returns that
+// result. If fn panics, all callers waiting for it panic with *Panic.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if e, ok := g.cache[key]; ok {
+		if g.now().Before(e.expires) {
+			g.mu.Unlock()
+			return e.v, nil
+		}
+		delete(g.cache, key)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		return c.result()
+	}
+	c := &call[V]{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+	return c.result()
+}
+
+// run calls fn for the call c and records the result.
+func (g *Group[K, V]) run(key K, c *call[V], fn func() (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.panic = newPanic(r)
+		}
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if !c.forgotten {
+			delete(g.calls, key)
+			if c.err == nil && c.panic == nil && g.TTL > 0 {
+				if g.cache == nil {
+					g.cache = make(map[K]cached[V])
+				}
+				now := g.now()
+				g.cache[key] = cached[V]{v: c.v, expires: now.Add(g.TTL)}
+				if len(g.cache) >= g.sweepAt {
+					g.sweep(now)
+				}
+			}
+		}
+		close(c.done)
+	}()
+	c.v, c.err = fn()
+}
+
+// sweep evicts the expired results, and doubles the size of the cache for the
+// next sweep so that the cost is amortized over the insertions. It must be
+// called with g.mu held.
+func (g *Group[K, V]) sweep(now time.Time) {
+	for key, e := range g.cache {
+		if !now.Before(e.expires) {
+			delete(g.cache, key)
+		}
+	}
+	g.sweepAt = max(2*len(g.cache), minSweep)
+}
+
+// result returns the result of the completed call.
+func (c *call[V]) result() (V, error) {
+	if c.panic != nil {
+		panic(c.panic)
+	}
+	return c.v, c.err
+}
+
+// Forget forgets the cached result and the in-flight call of the key, so that
+// the next Do with the key calls the function. The callers already waiting for
+// the in-flight call still get its result, but it is not cached.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.calls[key]; ok {
+		c.forgotten = true
+		delete(g.calls, key)
+	}
+	delete(g.cache, key)
+}
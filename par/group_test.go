@@ -0,0 +1,130 @@
+package par
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func ExampleGroup() {
+	// Concurrent lookups of the same name are collapsed, and the results
+	// are cached for a minute.
+	g := Group[string, int]{TTL: time.Minute}
+	var lookups atomic.Int32
+	lookup := func(name string) (int, error) {
+		lookups.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return len(name), nil
+	}
+	names := []string{"alice", "bob", "alice", "bob", "alice"}
+	lens := Map(names, func(name string) int {
+		n, _ := g.Do(name, func() (int, error) {
+			return lookup(name)
+		})
+		return n
+	})
+	fmt.Println(lens, lookups.Load())
+	// Output: [5 3 5 3 5] 2
+}
+
+func TestGroup_dedup(t *testing.T) {
+	// The callers missing the in-flight call get the cached result, so fn
+	// is called once however the goroutines are scheduled.
+	g := Group[int, int]{TTL: time.Hour}
+	var calls atomic.Int32
+	var arrived, wg sync.WaitGroup
+	results := make([]int, 10)
+	arrived.Add(len(results))
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			arrived.Done()
+			results[i], _ = g.Do(1, func() (int, error) {
+				calls.Add(1)
+				// Keep the call in flight until all callers arrive.
+				arrived.Wait()
+				return 42, nil
+			})
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("results[%d] = %d, want 42", i, v)
+		}
+	}
+}
+
+func TestGroup_TTLEvicts(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	g := Group[int, int]{TTL: time.Second, Clock: clock}
+	for i := 0; i < 10000; i++ {
+		_, _ = g.Do(i, func() (int, error) { return i, nil })
+		clock.After(100 * time.Millisecond)
+	}
+	// About 10 results are cached within TTL.
+	if n := len(g.cache); n > minSweep {
+		t.Errorf("%d results cached, want at most %d", n, minSweep)
+	}
+}
+
+func TestGroup_TTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	g := Group[string, int]{TTL: time.Minute, Clock: clock}
+	var calls int
+	fn := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+	for _, tc := range []struct {
+		advance time.Duration
+		forget  bool
+		want    int
+	}{
+		{0, false, 1},
+		{30 * time.Second, false, 1},
+		{30 * time.Second, false, 2},
+		{0, false, 2},
+		{0, true, 3},
+	} {
+		<-clock.After(tc.advance)
+		if tc.forget {
+			g.Forget("key")
+		}
+		if got, _ := g.Do("key", fn); got != tc.want {
+			t.Errorf("Do() at %v = %d, want %d", clock.Now().Unix(), got, tc.want)
+		}
+	}
+}
+
+func TestGroup_errorNotCached(t *testing.T) {
+	g := Group[string, int]{TTL: time.Hour}
+	errFail := errors.New("fail")
+	if _, err := g.Do("key", func() (int, error) { return 0, errFail }); !errors.Is(err, errFail) {
+		t.Errorf("Do() = %v, want %v", err, errFail)
+	}
+	if v, err := g.Do("key", func() (int, error) { return 1, nil }); v != 1 || err != nil {
+		t.Errorf("Do() = %v, %v, want 1, nil", v, err)
+	}
+}
+
+func TestGroup_panic(t *testing.T) {
+	var g Group[string, int]
+	defer func() {
+		// The key should be usable again.
+		if v, err := g.Do("key", func() (int, error) { return 1, nil }); v != 1 || err != nil {
+			t.Errorf("Do() = %v, %v, want 1, nil", v, err)
+		}
+	}()
+	defer expectPanic(t, "three")
+	_, _ = g.Do("key", func() (int, error) {
+		panic("three")
+	})
+}
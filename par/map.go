@@ -0,0 +1,29 @@
+package par
+
+import "context"
+
+// Map calls f for each element of in concurrently and returns the results in
+// the same order as in. The options are the same as For.
+func Map[T, U any](in []T, f func(v T) U, opts ...Option) []U {
+	out := make([]U, len(in))
+	newConfig(opts).run(len(in), func(i int) {
+		out[i] = f(in[i])
+	})
+	return out
+}
+
+// MapErr calls f for each element of in concurrently and returns the results in
+// the same order as in. If any call returns an error, it returns nil and the
+// error in the same way as ForErr.
+func MapErr[T, U any](ctx context.Context, in []T, f func(ctx context.Context, v T) (U, error), opts ...Option) ([]U, error) {
+	out := make([]U, len(in))
+	err := newConfig(opts).runErr(ctx, len(in), func(ctx context.Context, i int) error {
+		var err error
+		out[i], err = f(ctx, in[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
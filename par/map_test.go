@@ -0,0 +1,63 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func ExampleMap() {
+	words := []string{"apple", "banana", "cherry"}
+	lens := Map(words, func(w string) int {
+		return len(w)
+	}, WithLimit(2))
+	fmt.Println(lens)
+	// Output: [5 6 6]
+}
+
+func ExampleMapErr() {
+	nums, err := MapErr(context.Background(), []string{"1", "2", "3"}, func(_ context.Context, s string) (int, error) {
+		return strconv.Atoi(s)
+	})
+	fmt.Println(nums, err)
+	nums, err = MapErr(context.Background(), []string{"1", "b", "3"}, func(_ context.Context, s string) (int, error) {
+		return strconv.Atoi(s)
+	})
+	fmt.Println(nums, err)
+	// Output:
+	// [1 2 3] <nil>
+	// [] strconv.Atoi: parsing "b": invalid syntax
+}
+
+func TestMap_order(t *testing.T) {
+	in := make([]int, 1000)
+	for i := range in {
+		in[i] = i
+	}
+	for _, workers := range []int{0, 1, 3} {
+		out := Map(in, strconv.Itoa, WithLimit(workers))
+		for i, s := range out {
+			if s != strconv.Itoa(i) {
+				t.Fatalf("Map(workers=%d)[%d] = %q, want %q", workers, i, s, strconv.Itoa(i))
+			}
+		}
+	}
+}
+
+func TestMapErr_panic(t *testing.T) {
+	errPanic := errors.New("panic")
+	defer func() {
+		if p, ok := recover().(*Panic); !ok || !errors.Is(p, errPanic) {
+			t.Errorf("recovered %v, want *Panic wrapping %v", p, errPanic)
+		}
+	}()
+	_, _ = MapErr(context.Background(), []int{1, 2, 3}, func(_ context.Context, v int) (int, error) {
+		if v == 2 {
+			panic(errPanic)
+		}
+		return v, nil
+	}, WithLimit(1))
+	t.Error("MapErr should panic")
+}
@@ -0,0 +1,104 @@
+package par
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Observer receives the events of the tasks. A task is the same as described
+// in WithRate. The methods may be called concurrently from multiple
+// goroutines.
+type Observer interface {
+	// Start is called before the task i starts.
+	Start(i int)
+	// Finish is called after the task i returns. The err is the error
+	// returned by the task if any.
+	Finish(i int, elapsed time.Duration, err error)
+	// Panic is called when the task i panics with the value.
+	Panic(i int, elapsed time.Duration, value any)
+}
+
+// WithObserver sets the observer of the tasks. There is no overhead if no
+// observer is set.
+func WithObserver(o Observer) Option {
+	return func(c *config) {
+		c.observer = o
+	}
+}
+
+// observe calls f for the task i reporting the events to the observer. The
+// observer must be set.
+func (c *config) observe(i int, f func() error) error {
+	o := c.observer
+	o.Start(i)
+	start := c.clock.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			o.Panic(i, c.clock.Now().Sub(start), r)
+			panic(r)
+		}
+	}()
+	err := f()
+	o.Finish(i, c.clock.Now().Sub(start), err)
+	return err
+}
+
+// Progress is an observer counting the finished tasks, which can be polled
+// while the tasks are running.
+type Progress struct {
+	total    int
+	start    time.Time
+	now      func() time.Time
+	finished atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewProgress returns a new progress of the total number of tasks.
+func NewProgress(total int) *Progress {
+	return &Progress{total: total, start: time.Now(), now: time.Now}
+}
+
+// Start implements Observer.
+func (p *Progress) Start(int) {}
+
+// Finish implements Observer.
+func (p *Progress) Finish(_ int, _ time.Duration, err error) {
+	if err != nil {
+		p.failed.Add(1)
+	}
+	p.finished.Add(1)
+}
+
+// Panic implements Observer.
+func (p *Progress) Panic(int, time.Duration, any) {
+	p.failed.Add(1)
+	p.finished.Add(1)
+}
+
+// Total returns the total number of tasks.
+func (p *Progress) Total() int {
+	return p.total
+}
+
+// Completed returns the number of finished tasks including the failed ones.
+func (p *Progress) Completed() int {
+	return int(p.finished.Load())
+}
+
+// Failed returns the number of tasks which returned an error or panicked.
+func (p *Progress) Failed() int {
+	return int(p.failed.Load())
+}
+
+// ETA returns the estimated time until all tasks are completed, based on the
+// average time per task since the progress was created. It returns false if no
+// task is completed yet.
+func (p *Progress) ETA() (time.Duration, bool) {
+	completed := p.finished.Load()
+	if completed == 0 {
+		return 0, false
+	}
+	elapsed := p.now().Sub(p.start)
+	remaining := int64(p.total) - completed
+	return time.Duration(int64(elapsed) / completed * max(remaining, 0)), true
+}
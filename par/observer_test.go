@@ -0,0 +1,139 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func ExampleProgress() {
+	data := make([]int, 1000)
+	progress := NewProgress(len(data))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		For(len(data), func(i int) {
+			data[i] = i * i
+		}, WithLimit(4), WithObserver(progress))
+	}()
+	status := func() string {
+		eta, _ := progress.ETA()
+		return fmt.Sprintf("%d/%d done, ETA %v", progress.Completed(), progress.Total(), eta)
+	}
+	// Poll the progress while the tasks are running. A real program would
+	// show the status to the user instead of collecting it.
+	var polled []int
+	for {
+		select {
+		case <-done:
+			fmt.Println(status())
+			fmt.Println("polled counts never decrease:", slices.IsSorted(polled))
+			return
+		case <-time.After(time.Millisecond):
+			polled = append(polled, progress.Completed())
+		}
+	}
+	// Output:
+	// 1000/1000 done, ETA 0s
+	// polled counts never decrease: true
+}
+
+// recorder is an observer recording the events.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) record(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Start(i int) {
+	r.record("start %d", i)
+}
+
+func (r *recorder) Finish(i int, elapsed time.Duration, err error) {
+	r.record("finish %d %v %v", i, elapsed, err)
+}
+
+func (r *recorder) Panic(i int, elapsed time.Duration, value any) {
+	r.record("panic %d %v %v", i, elapsed, value)
+}
+
+func TestWithObserver(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	r := &recorder{}
+	err := ForErr(context.Background(), 3, func(context.Context, int) error {
+		<-clock.After(time.Second)
+		return errors.New("fail")
+	}, WithObserver(r), WithClock(clock), WithExecutor(Sequential()), JoinErrors())
+	if err == nil {
+		t.Error("ForErr() should return an error")
+	}
+	want := []string{
+		"start 0", "finish 0 1s fail",
+		"start 1", "finish 1 1s fail",
+		"start 2", "finish 2 1s fail",
+	}
+	if got := strings.Join(r.events, ", "); got != strings.Join(want, ", ") {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestWithObserver_panic(t *testing.T) {
+	r := &recorder{}
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	defer func() {
+		p, ok := recover().(*Panic)
+		if !ok || p.Value != "three" {
+			t.Errorf("recovered %v, want *Panic with three", p)
+			return
+		}
+		if !strings.Contains(string(p.Stack), "panicAtThree") {
+			t.Errorf("Stack does not contain the panicking function:\n%s", p.Stack)
+		}
+		want := "start 0, finish 0 0s <nil>, start 1, finish 1 0s <nil>, start 2, finish 2 0s <nil>, start 3, panic 3 0s three"
+		if got := strings.Join(r.events, ", "); got != want {
+			t.Errorf("events = %v, want %v", got, want)
+		}
+	}()
+	For(10, panicAtThree, WithObserver(r), WithClock(clock), WithExecutor(Sequential()))
+}
+
+func TestWithObserver_forEach(t *testing.T) {
+	progress := NewProgress(100)
+	err := ForEach(context.Background(), naturals, 3, func(_ context.Context, v int) error {
+		if v == 99 {
+			return errors.New("fail")
+		}
+		return nil
+	}, WithObserver(progress), WithExecutor(Sequential()))
+	if err == nil {
+		t.Error("ForEach() should return an error")
+	}
+	if progress.Completed() != 100 || progress.Failed() != 1 {
+		t.Errorf("completed %d and failed %d, want 100 and 1", progress.Completed(), progress.Failed())
+	}
+}
+
+func TestProgress_ETA(t *testing.T) {
+	now := time.Unix(0, 0)
+	p := &Progress{total: 10, start: now, now: func() time.Time { return now }}
+	if _, ok := p.ETA(); ok {
+		t.Error("ETA() should not be available before any task is completed")
+	}
+	for i := 0; i < 4; i++ {
+		p.Finish(i, 0, nil)
+	}
+	now = now.Add(8 * time.Second)
+	if eta, ok := p.ETA(); !ok || eta != 12*time.Second {
+		t.Errorf("ETA() = %v, %v, want 12s, true", eta, ok)
+	}
+}
@@ -0,0 +1,43 @@
+package par
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Panic is a panic value recovered in a goroutine spawned by this package. It is
+// re-panicked in the calling goroutine with the stack of the goroutine where
+// the panic occurred, so that the caller can recover from it.
+type Panic struct {
+	// Value is the original value passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine where the panic occurred.
+	Stack []byte
+}
+
+// newPanic returns a new Panic with the current stack. If r is already a
+// *Panic relayed from a nested call, it is returned as is.
+func newPanic(r any) *Panic {
+	if p, ok := r.(*Panic); ok {
+		return p
+	}
+	return &Panic{Value: r, Stack: debug.Stack()}
+}
+
+// Error returns the original panic value. The stack trace is not included to
+// keep the message short, and it is available in Stack.
+func (p *Panic) Error() string {
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Unwrap returns the original panic value if it is an error.
+func (p *Panic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// PanicValue returns the original panic value. Deferred handlers in other
+// packages, such as errors/must, use it to see through the relayed panic.
+func (p *Panic) PanicValue() any {
+	return p.Value
+}
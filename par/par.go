@@ -1,36 +1,274 @@
 // Package par provides convenient functions for concurrency and parallelism.
 package par
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
 
-// For calls f(i) in n goroutines where i is from 0 to n-1. The function blocks
-// until all goroutines finishes. It is recommended for dealing with multiple
-// objects/elements with same code.
-func For(n int, f func(i int)) {
+// Option configures how the functions in this package run.
+type Option func(*config)
+
+type config struct {
+	executor   Executor
+	limit      int
+	grain      int
+	joinErrors bool
+	clock      Clock
+	perSecond  float64
+	burst      int
+	limiter    *limiter
+	observer   Observer
+	schedule   *Schedule
+}
+
+// WithLimit limits the number of goroutines to n. Instead of spawning one
+// goroutine per call, a fixed pool of n goroutines takes the calls one by one.
+// If n is not positive, there is no limit. The tasks run on Pool(n) unless
+// WithExecutor is given.
+func WithLimit(n int) Option {
+	return func(c *config) {
+		c.limit = n
+	}
+}
+
+// WithGrain sets the number of elements processed sequentially in a goroutine by
+// the functions splitting a range into chunks, like ForRange. If n is not
+// positive, the range is split into as many chunks as the goroutines.
+func WithGrain(n int) Option {
+	return func(c *config) {
+		c.grain = n
+	}
+}
+
+// JoinErrors makes the error-returning functions report all errors joined by
+// errors.Join in the order of the indices, instead of the first error only. The
+// shared context is not canceled on failure so that all calls run.
+func JoinErrors() Option {
+	return func(c *config) {
+		c.joinErrors = true
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.perSecond > 0 {
+		c.limiter = newLimiter(c.clock, c.perSecond, c.burst)
+	}
+	return c
+}
+
+// wait blocks until a task can start under the rate limit.
+func (c *config) wait(ctx context.Context) error {
+	if c.limiter == nil {
+		return ctx.Err()
+	}
+	return c.limiter.wait(ctx)
+}
+
+// executorOrDefault returns the executor set by WithExecutor, or the one chosen
+// by the limit.
+func (c *config) executorOrDefault() Executor {
+	if c.executor != nil {
+		return c.executor
+	}
+	return Pool(c.limit)
+}
+
+// run calls f(i) for i from 0 to n-1 concurrently and blocks until all calls
+// return. If any call panics, calls not started yet are skipped and the panic
+// is re-panicked as *Panic in the calling goroutine after all calls return.
+func (c *config) run(n int, f func(i int)) {
+	if c.observer != nil {
+		g := f
+		f = func(i int) {
+			_ = c.observe(i, func() error {
+				g(i)
+				return nil
+			})
+		}
+	}
+	if c.limiter != nil {
+		g := f
+		f = func(i int) {
+			_ = c.limiter.wait(context.Background())
+			g(i)
+		}
+	}
+	c.exec(n, f, nil)
+}
+
+// exec is the same as run except that onPanic is called as soon as a call
+// panics, if it is not nil.
+func (c *config) exec(n int, f func(i int), onPanic func()) {
 	if n <= 0 {
 		return
 	}
-	var wg sync.WaitGroup
-	wg.Add(n)
-	for i := 0; i < n; i++ {
-		go func(i int) {
-			defer wg.Done()
+	var p atomic.Pointer[Panic]
+	recoverPanic := func() {
+		if r := recover(); r != nil {
+			if p.CompareAndSwap(nil, newPanic(r)) && onPanic != nil {
+				onPanic()
+			}
+		}
+	}
+	if c.schedule == nil {
+		c.executorOrDefault().Execute(n, func(i int) {
+			if p.Load() != nil {
+				return
+			}
+			defer recoverPanic()
 			f(i)
-		}(i)
+		})
+	} else {
+		workers := c.workers(n)
+		next := c.schedule.chunker(n, workers, c.grain)
+		c.executorOrDefault().Execute(workers, func(w int) {
+			defer recoverPanic()
+			for p.Load() == nil {
+				lo, hi, ok := next(w)
+				if !ok {
+					return
+				}
+				for i := lo; i < hi; i++ {
+					f(i)
+				}
+			}
+		})
+	}
+	if p := p.Load(); p != nil {
+		panic(p)
+	}
+}
+
+// runErr calls f(ctx, i) for i from 0 to n-1 concurrently and blocks until all
+// calls return. The context passed to f is canceled on the first error unless
+// errors are joined. Calls not started yet when the context is done are
+// skipped.
+func (c *config) runErr(ctx context.Context, n int, f func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu      sync.Mutex
+		errs    []indexedErr
+		skipped atomic.Bool
+	)
+	c.exec(n, func(i int) {
+		if c.wait(ctx) != nil {
+			skipped.Store(true)
+			return
+		}
+		var err error
+		if c.observer != nil {
+			err = c.observe(i, func() error {
+				return f(ctx, i)
+			})
+		} else {
+			err = f(ctx, i)
+		}
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if c.joinErrors || len(errs) == 0 {
+			errs = append(errs, indexedErr{i, err})
+		}
+		if !c.joinErrors {
+			cancel()
+		}
+	}, cancel)
+	switch {
+	case len(errs) == 0 && skipped.Load():
+		return context.Cause(ctx)
+	case len(errs) == 0:
+		return nil
+	case !c.joinErrors:
+		return errs[0].err
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].i < errs[j].i
+	})
+	joined := make([]error, len(errs))
+	for i, e := range errs {
+		joined[i] = e.err
 	}
-	wg.Wait()
+	return errors.Join(joined...)
+}
+
+type indexedErr struct {
+	i   int
+	err error
+}
+
+// For calls f(i) in n goroutines where i is from 0 to n-1. The function blocks
+// until all goroutines finishes. It is recommended for dealing with multiple
+// objects/elements with same code. Options such as WithLimit change how the
+// goroutines are spawned.
+func For(n int, f func(i int), opts ...Option) {
+	newConfig(opts).run(n, f)
+}
+
+// ForLimit calls f(i) where i is from 0 to n-1 using at most workers
+// goroutines. The function blocks until all calls finish. It is useful for
+// large n where a goroutine per element is too expensive. If workers is not
+// positive, it behaves the same as For.
+func ForLimit(n, workers int, f func(i int)) {
+	For(n, f, WithLimit(workers))
+}
+
+// ForErr calls f(ctx, i) in n goroutines where i is from 0 to n-1 and blocks
+// until all goroutines finish. On the first error, the context passed to f is
+// canceled and the first error is returned after all goroutines finish. Calls
+// not started yet by then are skipped. If ctx is done before all calls start,
+// the context error is returned unless f returned an error.
+func ForErr(ctx context.Context, n int, f func(ctx context.Context, i int) error, opts ...Option) error {
+	return newConfig(opts).runErr(ctx, n, f)
+}
+
+// Runner runs the functions in this package with the options. It is useful for
+// the functions taking variadic arguments, which cannot take options.
+type Runner struct {
+	opts []Option
+}
+
+// New returns a new runner with the given options.
+func New(opts ...Option) Runner {
+	return Runner{opts: opts}
+}
+
+// Do is the same as the package function Do except that the options of the
+// runner are applied.
+func (r Runner) Do(fs ...func()) {
+	For(len(fs), func(i int) {
+		fs[i]()
+	}, r.opts...)
+}
+
+// DoErr is the same as the package function DoErr except that the options of
+// the runner are applied.
+func (r Runner) DoErr(ctx context.Context, fs ...func(ctx context.Context) error) error {
+	return ForErr(ctx, len(fs), func(ctx context.Context, i int) error {
+		return fs[i](ctx)
+	}, r.opts...)
 }
 
 // Do calls the given functions in each goroutine and waits for all goroutines
 // finishes. It is recommended for running multiple different codes.
 func Do(fs ...func()) {
-	var wg sync.WaitGroup
-	wg.Add(len(fs))
-	for _, f := range fs {
-		go func(f func()) {
-			defer wg.Done()
-			f()
-		}(f)
-	}
-	wg.Wait()
+	New().Do(fs...)
+}
+
+// DoErr calls the given functions in each goroutine and waits for all
+// goroutines finish. On the first error, the context passed to the functions
+// is canceled and the first error is returned after all goroutines finish. Use
+// New to pass options like JoinErrors.
+func DoErr(ctx context.Context, fs ...func(ctx context.Context) error) error {
+	return New().DoErr(ctx, fs...)
 }
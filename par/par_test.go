@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"testing"
 
+	"github.com/jaeyeom/sugo/errors/must"
+	"github.com/jaeyeom/sugo/leaktest"
 	"github.com/jaeyeom/sugo/ranger"
 )
 
@@ -185,3 +190,286 @@ func ExampleDo_sumReturnErr() {
 	// 4950 <nil>
 	// 0 intentional error
 }
+
+func ExampleForLimit() {
+	data := make([]int, 100000)
+	// Only 8 goroutines are spawned to visit all elements.
+	ForLimit(len(data), 8, func(i int) {
+		data[i] = i * 2
+	})
+	fmt.Println(data[0], data[1], data[len(data)-1])
+	// Output: 0 2 199998
+}
+
+// checkVisited fails the test unless f(i) was called want(i) times for each
+// index i, counted in visited. The prefix is prepended to the error messages.
+func checkVisited(t *testing.T, prefix string, visited []atomic.Int32, want func(i int) int32) {
+	t.Helper()
+	for i := range visited {
+		if got, want := visited[i].Load(), want(i); got != want {
+			t.Errorf("%sf(%d) called %d times, want %d", prefix, i, got, want)
+		}
+	}
+}
+
+// once is the want function of checkVisited for the calls once per index.
+func once(int) int32 {
+	return 1
+}
+
+func TestForLimit(t *testing.T) {
+	for _, tc := range []struct {
+		n       int
+		workers int
+	}{
+		{0, 3},
+		{1, 3},
+		{10, 3},
+		{10, 10},
+		{10, 20},
+		{10, 0},
+		{10, -1},
+		{1000, 7},
+	} {
+		var running, maxRunning atomic.Int32
+		visited := make([]atomic.Int32, tc.n)
+		ForLimit(tc.n, tc.workers, func(i int) {
+			r := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if r <= m || maxRunning.CompareAndSwap(m, r) {
+					break
+				}
+			}
+			visited[i].Add(1)
+			running.Add(-1)
+		})
+		checkVisited(t, fmt.Sprintf("ForLimit(%d, %d): ", tc.n, tc.workers), visited, once)
+		if tc.workers > 0 && int(maxRunning.Load()) > tc.workers {
+			t.Errorf("ForLimit(%d, %d): %d calls ran concurrently", tc.n, tc.workers, maxRunning.Load())
+		}
+	}
+}
+
+// This example is the same as the example of Do above, but the error is
+// returned by DoErr without smuggling it out through captured variables.
+func ExampleDoErr_sumReturnErr() {
+	sumLines := func(intentionalError bool) (n int, err error) {
+		lines, nums, partial := make(chan string), make(chan int), make(chan int)
+		err = DoErr(context.Background(),
+			func(ctx context.Context) error {
+				defer close(lines)
+				for i := 0; i < 100; i++ {
+					next := fmt.Sprint(i)
+					if intentionalError && i == 55 {
+						next = ""
+					}
+					select {
+					case lines <- next:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+				return nil
+			},
+			func(ctx context.Context) error {
+				defer close(nums)
+				for line := range lines {
+					n, err := strconv.Atoi(line)
+					if err != nil {
+						return errors.New("intentional error")
+					}
+					select {
+					case nums <- n:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+				return nil
+			},
+			func(ctx context.Context) error {
+				defer close(partial)
+				return ForErr(ctx, 10, func(ctx context.Context, _ int) error {
+					select {
+					case partial <- sum(nums):
+					case <-ctx.Done():
+					}
+					return nil
+				})
+			},
+			func(context.Context) error {
+				n = sum(partial)
+				return nil
+			},
+		)
+		if err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+	fmt.Println(sumLines(false))
+	fmt.Println(sumLines(true))
+	// Output:
+	// 4950 <nil>
+	// 0 intentional error
+}
+
+func ExampleJoinErrors() {
+	err := ForErr(context.Background(), 5, func(_ context.Context, i int) error {
+		if i%2 == 1 {
+			return fmt.Errorf("odd number %d", i)
+		}
+		return nil
+	}, JoinErrors())
+	fmt.Println(err)
+	// Output:
+	// odd number 1
+	// odd number 3
+}
+
+func TestForErr_firstErrorCancels(t *testing.T) {
+	leaktest.Check(t)
+	errFirst := errors.New("first")
+	// Other calls block until the context is canceled.
+	err := ForErr(context.Background(), 10, func(ctx context.Context, i int) error {
+		if i == 3 {
+			return errFirst
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, errFirst) {
+		t.Errorf("ForErr() = %v, want %v", err, errFirst)
+	}
+}
+
+func TestForErr_skipsAfterError(t *testing.T) {
+	errFirst := errors.New("first")
+	var calls atomic.Int32
+	err := ForErr(context.Background(), 100, func(_ context.Context, _ int) error {
+		calls.Add(1)
+		return errFirst
+	}, WithLimit(1))
+	if !errors.Is(err, errFirst) {
+		t.Errorf("ForErr() = %v, want %v", err, errFirst)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("f called %d times, want 1", got)
+	}
+}
+
+func TestForErr_canceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ForErr(ctx, 3, func(context.Context, int) error {
+		t.Error("f should not be called")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForErr() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestForErr_noError(t *testing.T) {
+	if err := ForErr(context.Background(), 3, func(context.Context, int) error {
+		return nil
+	}); err != nil {
+		t.Errorf("ForErr() = %v, want nil", err)
+	}
+}
+
+func TestRunner_DoErr(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	err := New(JoinErrors()).DoErr(context.Background(),
+		func(context.Context) error { return errA },
+		func(context.Context) error { return nil },
+		func(context.Context) error { return errB },
+	)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("DoErr() = %v, want both %v and %v", err, errA, errB)
+	}
+}
+
+// Panics raised by must package in the goroutines are re-panicked in the
+// calling goroutine, so that must.ReturnErr deferred in the caller catches them.
+func ExampleFor_mustReturnErr() {
+	parseAll := func(ss []string) (nums []int, err error) {
+		defer must.ReturnErr(&err)
+		nums = make([]int, len(ss))
+		For(len(ss), func(i int) {
+			nums[i] = must.Get(strconv.Atoi(ss[i]))
+		})
+		return nums, nil
+	}
+	fmt.Println(parseAll([]string{"1", "2", "3"}))
+	_, err := parseAll([]string{"1", "b", "3"})
+	fmt.Println(err)
+	// Output:
+	// [1 2 3] <nil>
+	// strconv.Atoi: parsing "b": invalid syntax
+}
+
+func panicAtThree(i int) {
+	if i == 3 {
+		panic("three")
+	}
+}
+
+// expectPanic fails the test unless a *Panic with the value want is recovered.
+// It must be deferred directly to recover the panic.
+func expectPanic(t *testing.T, want any) {
+	t.Helper()
+	if p, ok := recover().(*Panic); !ok || p.Value != want {
+		t.Errorf("recovered %v, want *Panic with %v", p, want)
+	}
+}
+
+func TestFor_panic(t *testing.T) {
+	for _, workers := range []int{0, 2} {
+		func() {
+			defer func() {
+				p, ok := recover().(*Panic)
+				if !ok {
+					t.Fatalf("recovered value is not *Panic")
+				}
+				if p.Value != "three" {
+					t.Errorf("Value = %v, want three", p.Value)
+				}
+				if !strings.Contains(string(p.Stack), "panicAtThree") {
+					t.Errorf("Stack does not contain the panicking function:\n%s", p.Stack)
+				}
+				if got, want := p.Error(), "panic: three"; got != want {
+					t.Errorf("Error() = %q, want %q", got, want)
+				}
+			}()
+			For(10, panicAtThree, WithLimit(workers))
+		}()
+	}
+}
+
+func TestForErr_panicCancels(t *testing.T) {
+	leaktest.Check(t)
+	errPanic := errors.New("panic")
+	defer func() {
+		p, ok := recover().(*Panic)
+		if !ok || !errors.Is(p, errPanic) {
+			t.Errorf("recovered %v, want *Panic wrapping %v", p, errPanic)
+		}
+	}()
+	// Other calls block until the context is canceled by the panic.
+	_ = ForErr(context.Background(), 10, func(ctx context.Context, i int) error {
+		if i == 3 {
+			panic(errPanic)
+		}
+		<-ctx.Done()
+		return nil
+	})
+	t.Error("ForErr should panic")
+}
+
+func TestFor_nestedPanic(t *testing.T) {
+	defer expectPanic(t, "three")
+	For(2, func(int) {
+		For(5, panicAtThree)
+	})
+}
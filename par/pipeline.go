@@ -0,0 +1,253 @@
+package par
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Pipeline runs stages connected by channels. Each stage runs in its own
+// goroutines until its input channel is closed or the pipeline is canceled.
+// When any stage fails, the context of the pipeline is canceled and all stages
+// return without leaking goroutines.
+//
+// A pipeline starts with Source, chains stages with Stage.Run and ends with
+// Drain. The output channel of the last stage must be drained, otherwise Wait
+// blocks forever.
+type Pipeline struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	err         error
+	panic       atomic.Pointer[Panic]
+	interrupted atomic.Bool
+}
+
+// NewPipeline returns a new pipeline which is canceled when ctx is done.
+func NewPipeline(ctx context.Context) *Pipeline {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pipeline{ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context of the pipeline. It is canceled when any stage
+// fails.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Wait blocks until all stages return and returns the first error. If any stage
+// panicked, the panic is re-panicked as *Panic. If the context given to
+// NewPipeline is done before the stages finish, the context error is returned.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	if pv := p.panic.Load(); pv != nil {
+		panic(pv)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil && p.interrupted.Load() {
+		return context.Cause(p.ctx)
+	}
+	return p.err
+}
+
+// fail records the first error and cancels the pipeline.
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.cancel()
+}
+
+// done returns true if the pipeline is canceled and records the interruption.
+func (p *Pipeline) done() bool {
+	if p.ctx.Err() == nil {
+		return false
+	}
+	p.interrupted.Store(true)
+	return true
+}
+
+// spawn runs f in a new goroutine of the pipeline. An error or a panic from f
+// cancels the pipeline.
+func (p *Pipeline) spawn(f func() error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				p.panic.CompareAndSwap(nil, newPanic(r))
+				p.cancel()
+			}
+		}()
+		if err := f(); err != nil {
+			p.fail(err)
+		}
+	}()
+}
+
+// send sends v to out unless the pipeline is canceled. It returns false if the
+// pipeline is canceled.
+func send[T any](p *Pipeline, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-p.ctx.Done():
+		p.done()
+		return false
+	}
+}
+
+// receive receives a value from in unless the pipeline is canceled. It returns
+// false if in is closed or the pipeline is canceled.
+func receive[T any](p *Pipeline, in <-chan T) (T, bool) {
+	select {
+	case v, ok := <-in:
+		return v, ok
+	case <-p.ctx.Done():
+		p.done()
+		var zero T
+		return zero, false
+	}
+}
+
+// Source starts a stage producing values by gen and returns the output channel
+// with the buffer size. The gen function should call emit for each value and
+// stop when emit returns false, which means the pipeline is canceled. The
+// output channel is closed when gen returns.
+func Source[T any](p *Pipeline, buffer int, gen func(ctx context.Context, emit func(v T) bool) error) <-chan T {
+	out := make(chan T, buffer)
+	p.spawn(func() error {
+		defer close(out)
+		return gen(p.ctx, func(v T) bool {
+			return send(p, out, v)
+		})
+	})
+	return out
+}
+
+// Stage is a pipeline stage transforming values of type T into values of type
+// U.
+type Stage[T, U any] struct {
+	// Workers is the number of goroutines calling Func. It is 1 if not
+	// positive.
+	Workers int
+	// Buffer is the buffer size of the output channel.
+	Buffer int
+	// Ordered makes the output in the same order as the input even if
+	// Workers is greater than 1.
+	Ordered bool
+	// Func transforms an input value. An error cancels the pipeline.
+	Func func(ctx context.Context, v T) (U, error)
+}
+
+// Run starts the stage reading values from in and returns the output channel.
+// The output channel is closed after in is closed and all values are
+// processed, or the pipeline is canceled.
+func (s Stage[T, U]) Run(p *Pipeline, in <-chan T) <-chan U {
+	out := make(chan U, s.Buffer)
+	workers := max(s.Workers, 1)
+	if s.Ordered && workers > 1 {
+		s.runOrdered(p, in, out, workers)
+		return out
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		p.spawn(func() error {
+			defer wg.Done()
+			for {
+				v, ok := receive(p, in)
+				if !ok {
+					return nil
+				}
+				u, err := s.Func(p.ctx, v)
+				if err != nil {
+					return err
+				}
+				if !send(p, out, u) {
+					return nil
+				}
+			}
+		})
+	}
+	p.spawn(func() error {
+		wg.Wait()
+		close(out)
+		return nil
+	})
+	return out
+}
+
+// runOrdered dispatches the values to the workers with a slot for each result,
+// and emits the results in the order of the slots.
+func (s Stage[T, U]) runOrdered(p *Pipeline, in <-chan T, out chan<- U, workers int) {
+	type job struct {
+		v    T
+		slot chan U
+	}
+	jobs := make(chan job)
+	slots := make(chan chan U, workers+s.Buffer)
+	p.spawn(func() error {
+		defer close(jobs)
+		defer close(slots)
+		for {
+			v, ok := receive(p, in)
+			if !ok {
+				return nil
+			}
+			slot := make(chan U, 1)
+			if !send(p, slots, slot) || !send(p, jobs, job{v, slot}) {
+				return nil
+			}
+		}
+	})
+	for w := 0; w < workers; w++ {
+		p.spawn(func() error {
+			for {
+				j, ok := receive(p, jobs)
+				if !ok {
+					return nil
+				}
+				u, err := s.Func(p.ctx, j.v)
+				if err != nil {
+					return err
+				}
+				j.slot <- u
+			}
+		})
+	}
+	p.spawn(func() error {
+		defer close(out)
+		for {
+			slot, ok := receive(p, slots)
+			if !ok {
+				return nil
+			}
+			u, ok := receive(p, slot)
+			if !ok || !send(p, out, u) {
+				return nil
+			}
+		}
+	})
+}
+
+// Drain starts the last stage calling f for each value from in until in is
+// closed or the pipeline is canceled. An error from f cancels the pipeline.
+func Drain[T any](p *Pipeline, in <-chan T, f func(ctx context.Context, v T) error) {
+	p.spawn(func() error {
+		for {
+			v, ok := receive(p, in)
+			if !ok {
+				return nil
+			}
+			if err := f(p.ctx, v); err != nil {
+				return err
+			}
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/jaeyeom/sugo/leaktest"
+)
+
+func ExamplePipeline() {
+	p := NewPipeline(context.Background())
+	lines := Source(p, 0, func(_ context.Context, emit func(string) bool) error {
+		for i := 1; i <= 5; i++ {
+			if !emit(fmt.Sprint(i)) {
+				return nil
+			}
+		}
+		return nil
+	})
+	nums := Stage[string, int]{
+		Workers: 3,
+		Ordered: true,
+		Func: func(_ context.Context, line string) (int, error) {
+			return strconv.Atoi(line)
+		},
+	}.Run(p, lines)
+	squares := Stage[int, int]{
+		Workers: 3,
+		Ordered: true,
+		Func: func(_ context.Context, n int) (int, error) {
+			return n * n, nil
+		},
+	}.Run(p, nums)
+	Drain(p, squares, func(_ context.Context, n int) error {
+		fmt.Println(n)
+		return nil
+	})
+	if err := p.Wait(); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// 1
+	// 4
+	// 9
+	// 16
+	// 25
+}
+
+// This example is the same as ExampleDo_sumReturnErr in par_test.go, but
+// written as a pipeline.
+func ExamplePipeline_sumReturnErr() {
+	sumLines := func(intentionalError bool) (int, error) {
+		p := NewPipeline(context.Background())
+		lines := Source(p, 0, func(_ context.Context, emit func(string) bool) error {
+			for i := 0; i < 100; i++ {
+				next := fmt.Sprint(i)
+				if intentionalError && i == 55 {
+					next = ""
+				}
+				if !emit(next) {
+					return nil
+				}
+			}
+			return nil
+		})
+		nums := Stage[string, int]{
+			Workers: 10,
+			Func: func(_ context.Context, line string) (int, error) {
+				n, err := strconv.Atoi(line)
+				if err != nil {
+					return 0, errors.New("intentional error")
+				}
+				return n, nil
+			},
+		}.Run(p, lines)
+		var n int
+		Drain(p, nums, func(_ context.Context, v int) error {
+			n += v
+			return nil
+		})
+		if err := p.Wait(); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+	fmt.Println(sumLines(false))
+	fmt.Println(sumLines(true))
+	// Output:
+	// 4950 <nil>
+	// 0 intentional error
+}
+
+func runSquares(ctx context.Context, n int, s Stage[int, int]) ([]int, error) {
+	p := NewPipeline(ctx)
+	nums := Source(p, 1, func(_ context.Context, emit func(int) bool) error {
+		for i := 0; i < n; i++ {
+			if !emit(i) {
+				return nil
+			}
+		}
+		return nil
+	})
+	var out []int
+	Drain(p, s.Run(p, nums), func(_ context.Context, v int) error {
+		out = append(out, v)
+		return nil
+	})
+	return out, p.Wait()
+}
+
+func TestStage_ordered(t *testing.T) {
+	leaktest.Check(t)
+	for _, workers := range []int{0, 1, 4} {
+		out, err := runSquares(context.Background(), 1000, Stage[int, int]{
+			Workers: workers,
+			Buffer:  2,
+			Ordered: true,
+			Func: func(_ context.Context, v int) (int, error) {
+				return v * v, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("workers=%d: Wait() = %v", workers, err)
+		}
+		if len(out) != 1000 {
+			t.Fatalf("workers=%d: got %d values, want 1000", workers, len(out))
+		}
+		for i, v := range out {
+			if v != i*i {
+				t.Fatalf("workers=%d: out[%d] = %d, want %d", workers, i, v, i*i)
+			}
+		}
+	}
+}
+
+func TestStage_unordered(t *testing.T) {
+	out, err := runSquares(context.Background(), 1000, Stage[int, int]{
+		Workers: 8,
+		Func: func(_ context.Context, v int) (int, error) {
+			return v, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	var total int
+	for _, v := range out {
+		total += v
+	}
+	if len(out) != 1000 || total != 999*1000/2 {
+		t.Errorf("got %d values with sum %d", len(out), total)
+	}
+}
+
+func TestStage_error(t *testing.T) {
+	leaktest.Check(t)
+	errStage := errors.New("stage")
+	for _, ordered := range []bool{false, true} {
+		_, err := runSquares(context.Background(), 1000, Stage[int, int]{
+			Workers: 4,
+			Ordered: ordered,
+			Func: func(_ context.Context, v int) (int, error) {
+				if v == 500 {
+					return 0, errStage
+				}
+				return v, nil
+			},
+		})
+		if !errors.Is(err, errStage) {
+			t.Errorf("ordered=%v: Wait() = %v, want %v", ordered, err, errStage)
+		}
+	}
+}
+
+func TestStage_panic(t *testing.T) {
+	leaktest.Check(t)
+	defer expectPanic(t, "three")
+	_, _ = runSquares(context.Background(), 10, Stage[int, int]{
+		Workers: 2,
+		Ordered: true,
+		Func: func(_ context.Context, v int) (int, error) {
+			panicAtThree(v)
+			return v, nil
+		},
+	})
+	t.Error("Wait should panic")
+}
+
+func TestPipeline_canceled(t *testing.T) {
+	leaktest.Check(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPipeline(ctx)
+	nums := Source(p, 0, func(_ context.Context, emit func(int) bool) error {
+		for i := 0; emit(i); i++ {
+		}
+		return nil
+	})
+	Drain(p, nums, func(_ context.Context, v int) error {
+		if v == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if err := p.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,110 @@
+package par
+
+import (
+	"runtime"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+// defaultLimit limits the goroutines to runtime.GOMAXPROCS(0) unless a limit is
+// set. The functions splitting a range into chunks call it so that the chunks
+// run on a pool of that size.
+func (c *config) defaultLimit() {
+	if c.limit <= 0 {
+		c.limit = runtime.GOMAXPROCS(0)
+	}
+}
+
+// chunks returns the number of chunks to split size elements into. Without
+// the grain, there are as many chunks as the goroutines.
+func (c *config) chunks(size int) int {
+	if c.grain > 0 {
+		return (size + c.grain - 1) / c.grain
+	}
+	return c.workers(size)
+}
+
+// ForRange calls f(v) for each number v in fi concurrently and blocks until all
+// calls finish. Unlike For, it does not spawn a goroutine per element. The range
+// is partitioned into chunks which are processed by at most
+// runtime.GOMAXPROCS(0) goroutines, or the limit given by WithLimit. The chunk
+// size can be set by WithGrain. By default, the range is split statically;
+// WithSchedule assigns the chunks dynamically instead.
+func ForRange(fi ranger.FiniteIth, f func(v int), opts ...Option) {
+	if fi.Size <= 0 {
+		return
+	}
+	c := newConfig(opts)
+	if c.schedule != nil {
+		c.run(fi.Size, func(i int) {
+			f(fi.Ith(i))
+		})
+		return
+	}
+	c.defaultLimit()
+	parts := c.chunks(fi.Size)
+	c.run(parts, func(i int) {
+		p := fi.Partition(i, parts)
+		for j := 0; j < p.Size; j++ {
+			f(p.Ith(j))
+		}
+	})
+}
+
+// ForND splits ndr into rectangular tiles of the size tile, and calls f(nd) for
+// each N-dimensional index nd in ndr, where the tiles are processed
+// concurrently. The indices are the same as ndr.FromIth returns, but they are
+// computed incrementally within a tile. The length of tile must be the same as
+// the number of dimensions of ndr. If tile has a non-positive size in a
+// dimension, the tiles are not split in the dimension.
+//
+// The nd passed to f is reused for the next index in the same tile. Copy it if
+// f needs to retain it.
+func ForND(ndr ranger.NDRange, tile ranger.ND, f func(nd ranger.ND), opts ...Option) {
+	dim := len(ndr.Iths)
+	if len(tile) != dim {
+		panic("tile dimension mismatch")
+	}
+	sizes := make(ranger.ND, dim)
+	grid := make(ranger.NDShape, dim)
+	numTiles := 1
+	for d, fi := range ndr.Iths {
+		if fi.Size <= 0 {
+			return
+		}
+		sizes[d] = fi.Size
+		if tile[d] > 0 {
+			sizes[d] = min(tile[d], fi.Size)
+		}
+		grid[d] = (fi.Size + sizes[d] - 1) / sizes[d]
+		numTiles *= grid[d]
+	}
+	newConfig(opts).run(numTiles, func(t int) {
+		lo := grid.FromIth(t)
+		hi := make(ranger.ND, dim)
+		pos := make(ranger.ND, dim)
+		nd := make(ranger.ND, dim)
+		for d, fi := range ndr.Iths {
+			lo[d] *= sizes[d]
+			hi[d] = min(lo[d]+sizes[d], fi.Size)
+			pos[d] = lo[d]
+			nd[d] = fi.Ith(pos[d])
+		}
+		for {
+			f(nd)
+			d := dim - 1
+			for ; d >= 0; d-- {
+				pos[d]++
+				if pos[d] < hi[d] {
+					nd[d] = ndr.Iths[d].Ith(pos[d])
+					break
+				}
+				pos[d] = lo[d]
+				nd[d] = ndr.Iths[d].Ith(pos[d])
+			}
+			if d < 0 {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package par
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+func ExampleForRange_loopWithStep() {
+	data := []int{1, 2, 3, 4, 5, 6}
+	// Index 1, 3, 5
+	ForRange(ranger.Range(1, len(data), 2), func(i int) {
+		data[i] += 10
+	})
+	fmt.Println(data)
+	// Output: [1 12 3 14 5 16]
+}
+
+func TestForRange(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		fi   ranger.FiniteIth
+		opts []Option
+	}{
+		{"empty", ranger.Range(0, 0, 1), nil},
+		{"single", ranger.Range(3, 4, 1), nil},
+		{"default", ranger.Range(0, 1000, 1), nil},
+		{"step", ranger.Range(999, -1, -3), nil},
+		{"grain", ranger.Range(0, 1000, 1), []Option{WithGrain(7)}},
+		{"limit", ranger.Range(0, 1000, 1), []Option{WithLimit(3)}},
+		{"grain and limit", ranger.Range(0, 1000, 1), []Option{WithGrain(10), WithLimit(2)}},
+		{"large grain", ranger.Range(0, 10, 1), []Option{WithGrain(100)}},
+		{"indices", ranger.FromIndices([]int{5, 3, 9}), nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			visited := make([]atomic.Int32, 1000)
+			ForRange(tc.fi, func(v int) {
+				visited[v].Add(1)
+			}, tc.opts...)
+			want := make([]int32, len(visited))
+			for i := 0; i < tc.fi.Size; i++ {
+				want[tc.fi.Ith(i)]++
+			}
+			checkVisited(t, "", visited, func(i int) int32 { return want[i] })
+		})
+	}
+}
+
+func ExampleForND() {
+	// The input data shape.
+	rows, cols := 4, 5
+	nds := ranger.NDShape(ranger.ND{rows, cols})
+	data := make([]int, rows*cols)
+	// Fill the inner 2x3 area in 1x2 tiles.
+	ndr := ranger.NDRange{
+		Shape: nds,
+		Iths:  []ranger.FiniteIth{ranger.Range(1, 3, 1), ranger.Range(1, 4, 1)},
+	}
+	ForND(ndr, ranger.ND{1, 2}, func(nd ranger.ND) {
+		data[nds.ToIth(nd)] = 1
+	})
+	for i := 0; i < rows; i++ {
+		fmt.Println(data[i*cols : (i+1)*cols])
+	}
+	// Output:
+	// [0 0 0 0 0]
+	// [0 1 1 1 0]
+	// [0 1 1 1 0]
+	// [0 0 0 0 0]
+}
+
+func TestForND(t *testing.T) {
+	shape := ranger.NDShape(ranger.ND{6, 7, 8})
+	ndr := ranger.NDRange{
+		Shape: shape,
+		Iths: []ranger.FiniteIth{
+			ranger.Range(5, -1, -2),
+			ranger.Range(0, 7, 1),
+			ranger.FromIndices([]int{7, 2, 3}),
+		},
+	}
+	for _, tile := range []ranger.ND{
+		{1, 1, 1},
+		{2, 3, 2},
+		{0, 0, 0},
+		{10, 10, 10},
+		{-1, 4, 1},
+	} {
+		visited := make([]atomic.Int32, 6*7*8)
+		ForND(ndr, tile, func(nd ranger.ND) {
+			visited[shape.ToIth(nd)].Add(1)
+		})
+		want := make([]int32, len(visited))
+		for i := 0; i < ndr.ComputeSize(); i++ {
+			want[shape.ToIth(ndr.FromIth(i))]++
+		}
+		checkVisited(t, fmt.Sprintf("tile %v: ", tile), visited, func(i int) int32 { return want[i] })
+	}
+}
+
+func TestForND_empty(t *testing.T) {
+	ndr := ranger.NDRange{
+		Shape: ranger.NDShape(ranger.ND{3, 3}),
+		Iths:  []ranger.FiniteIth{ranger.Range(0, 3, 1), ranger.Range(0, 0, 1)},
+	}
+	ForND(ndr, ranger.ND{1, 1}, func(ranger.ND) {
+		t.Error("f should not be called")
+	})
+}
@@ -0,0 +1,90 @@
+package par
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to the functions in this package.
+// Tests may replace it with a fake clock by WithClock not to sleep.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock sets the clock used for the rate limit. The default is the system
+// clock.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// WithRate limits the rate of starting tasks to perSecond tasks per second,
+// allowing bursts of up to burst tasks. A task is a call of the function for
+// For and Map, a chunk for ForRange and a value for ForEach. The rate applies
+// to each call of the functions separately. It can be used with WithLimit to
+// cap the concurrency as well. If perSecond is not positive, there is no rate
+// limit.
+func WithRate(perSecond float64, burst int) Option {
+	return func(c *config) {
+		c.perSecond = perSecond
+		c.burst = burst
+	}
+}
+
+// limiter is a token bucket rate limiter implemented by the generic cell rate
+// algorithm.
+type limiter struct {
+	clock    Clock
+	interval time.Duration
+	// tolerance is how much earlier than the theoretical arrival time a
+	// task can start, which allows bursts.
+	tolerance time.Duration
+	mu        sync.Mutex
+	// tat is the theoretical arrival time of the next task.
+	tat time.Time
+}
+
+func newLimiter(clock Clock, perSecond float64, burst int) *limiter {
+	interval := time.Duration(float64(time.Second) / perSecond)
+	return &limiter{
+		clock:     clock,
+		interval:  interval,
+		tolerance: time.Duration(max(burst, 1)-1) * interval,
+	}
+}
+
+// wait blocks until a task can start. It returns the context error if ctx is
+// done before then.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := l.clock.Now()
+	tat := l.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	l.tat = tat.Add(l.interval)
+	delay := tat.Add(-l.tolerance).Sub(now)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
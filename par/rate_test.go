@@ -0,0 +1,91 @@
+package par
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock advancing the time instantly instead of sleeping.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// since returns the durations from start to each of times.
+func since(start time.Time, times []time.Time) []time.Duration {
+	ds := make([]time.Duration, len(times))
+	for i, t := range times {
+		ds[i] = t.Sub(start)
+	}
+	return ds
+}
+
+func TestWithRate(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var starts []time.Time
+	For(6, func(int) {
+		starts = append(starts, clock.Now())
+	}, WithRate(2, 3), WithClock(clock), WithExecutor(Sequential()))
+	ms := time.Millisecond
+	want := []time.Duration{0, 0, 0, 500 * ms, 1000 * ms, 1500 * ms}
+	if got := since(time.Unix(0, 0), starts); !slices.Equal(got, want) {
+		t.Errorf("tasks started at %v, want %v", got, want)
+	}
+}
+
+func TestWithRate_forEach(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var calls atomic.Int32
+	err := ForEach(context.Background(), slices.Values([]int{1, 2, 3, 4}), 2, func(context.Context, int) error {
+		calls.Add(1)
+		return nil
+	}, WithRate(10, 1), WithClock(clock))
+	if err != nil {
+		t.Fatalf("ForEach() = %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("f called %d times, want 4", calls.Load())
+	}
+	// The rate applies to the values, not the workers.
+	if got, want := clock.Now().Sub(time.Unix(0, 0)), 300*time.Millisecond; got != want {
+		t.Errorf("clock advanced %v, want %v", got, want)
+	}
+}
+
+func TestWithRate_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	// The system clock is used, but it does not sleep long since the
+	// context is canceled by the first call.
+	err := ForErr(ctx, 3, func(context.Context, int) error {
+		calls++
+		cancel()
+		return nil
+	}, WithRate(0.001, 1), WithExecutor(Sequential()))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("ForErr() = %v, want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("f called %d times, want 1", calls)
+	}
+}
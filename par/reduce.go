@@ -0,0 +1,40 @@
+package par
+
+import (
+	"runtime"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+// Reduce partitions fi into parts, reduces each part in its own goroutine and
+// returns the combined result. Each number in a part is mapped by mapper and
+// combined from left to right. The partial results are combined in partition
+// order, so the result is deterministic for the same parts even if combine is
+// not commutative, like floating-point addition. If parts is not positive,
+// runtime.GOMAXPROCS(0) is used. It returns the zero value if fi is empty.
+func Reduce[T any](fi ranger.FiniteIth, parts int, mapper func(idx int) T, combine func(T, T) T, opts ...Option) T {
+	var zero T
+	if fi.Size <= 0 {
+		return zero
+	}
+	if parts <= 0 {
+		parts = runtime.GOMAXPROCS(0)
+	}
+	// Avoid empty parts which cannot be reduced without the identity.
+	partSize := (fi.Size + parts - 1) / parts
+	parts = (fi.Size + partSize - 1) / partSize
+	partials := make([]T, parts)
+	newConfig(opts).run(parts, func(i int) {
+		p := fi.Partition(i, parts)
+		acc := mapper(p.Ith(0))
+		for j := 1; j < p.Size; j++ {
+			acc = combine(acc, mapper(p.Ith(j)))
+		}
+		partials[i] = acc
+	})
+	acc := partials[0]
+	for _, partial := range partials[1:] {
+		acc = combine(acc, partial)
+	}
+	return acc
+}
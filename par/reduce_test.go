@@ -0,0 +1,60 @@
+package par
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+func ExampleReduce() {
+	data := []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0}
+	add := func(a, b float64) float64 { return a + b }
+	// Sum of the elements at the odd indices.
+	fi := ranger.Range(1, len(data), 2)
+	sum := Reduce(fi, 3, func(i int) float64 {
+		return data[i]
+	}, add)
+	fmt.Printf("%.1f\n", sum)
+	// Output: 3.0
+}
+
+func TestReduce(t *testing.T) {
+	concat := func(a, b string) string { return a + b }
+	for _, tc := range []struct {
+		fi    ranger.FiniteIth
+		parts int
+		want  string
+	}{
+		{ranger.Range(0, 0, 1), 3, ""},
+		{ranger.Range(0, 1, 1), 3, "0"},
+		{ranger.Range(0, 5, 1), 4, "01234"},
+		{ranger.Range(0, 10, 1), 3, "0123456789"},
+		{ranger.Range(9, -1, -1), 0, "9876543210"},
+		{ranger.FromIndices([]int{3, 1, 4, 1, 5}), 2, "31415"},
+	} {
+		got := Reduce(tc.fi, tc.parts, func(i int) string {
+			return fmt.Sprint(i)
+		}, concat)
+		if got != tc.want {
+			t.Errorf("Reduce(parts=%d) = %q, want %q", tc.parts, got, tc.want)
+		}
+	}
+}
+
+func TestReduce_deterministic(t *testing.T) {
+	fi := ranger.Range(0, 100000, 1)
+	sum := func() float64 {
+		return Reduce(fi, 7, func(i int) float64 {
+			return 1 / float64(i+1)
+		}, func(a, b float64) float64 {
+			return a + b
+		})
+	}
+	want := sum()
+	for i := 0; i < 10; i++ {
+		if got := sum(); got != want {
+			t.Fatalf("Reduce() = %v, want %v", got, want)
+		}
+	}
+}
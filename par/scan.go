@@ -0,0 +1,73 @@
+package par
+
+import "github.com/jaeyeom/sugo/ranger"
+
+// Scan returns the inclusive prefix scan of in, where the ith result is
+// in[0] op in[1] op ... op in[i]. The op must be associative, but it need not
+// be commutative. The input is partitioned into chunks in the same way as
+// ForRange. Each chunk is scanned concurrently, and then the totals of the
+// preceding chunks are combined into each chunk concurrently.
+func Scan[T any](in []T, op func(T, T) T, opts ...Option) []T {
+	out := make([]T, len(in))
+	scan(newConfig(opts), out, in, op)
+	return out
+}
+
+// ExclusiveScan returns the exclusive prefix scan of in, where the first result
+// is identity and the ith result is in[0] op in[1] op ... op in[i-1]. The op
+// must be associative, but it need not be commutative.
+func ExclusiveScan[T any](in []T, identity T, op func(T, T) T, opts ...Option) []T {
+	out := make([]T, len(in))
+	if len(in) == 0 {
+		return out
+	}
+	out[0] = identity
+	scan(newConfig(opts), out[1:], in[:len(in)-1], op)
+	return out
+}
+
+// scan writes the inclusive prefix scan of in into out by the two-pass block
+// algorithm.
+func scan[T any](c *config, out, in []T, op func(T, T) T) {
+	n := len(in)
+	if n == 0 {
+		return
+	}
+	c.defaultLimit()
+	parts := c.chunks(n)
+	fi := ranger.Range(0, n, 1)
+	c.run(parts, func(i int) {
+		p := fi.Partition(i, parts)
+		if p.Size == 0 {
+			return
+		}
+		acc := in[p.Ith(0)]
+		out[p.Ith(0)] = acc
+		for j := 1; j < p.Size; j++ {
+			acc = op(acc, in[p.Ith(j)])
+			out[p.Ith(j)] = acc
+		}
+	})
+	// offsets[i] is the total of the chunks before the chunk i. Only the
+	// leading chunks are non-empty.
+	offsets := make([]T, parts)
+	nonEmpty := 1
+	for ; nonEmpty < parts; nonEmpty++ {
+		prev := fi.Partition(nonEmpty-1, parts)
+		if fi.Partition(nonEmpty, parts).Size == 0 {
+			break
+		}
+		total := out[prev.Ith(prev.Size-1)]
+		if nonEmpty == 1 {
+			offsets[nonEmpty] = total
+		} else {
+			offsets[nonEmpty] = op(offsets[nonEmpty-1], total)
+		}
+	}
+	c.run(nonEmpty-1, func(i int) {
+		p := fi.Partition(i+1, parts)
+		for j := 0; j < p.Size; j++ {
+			out[p.Ith(j)] = op(offsets[i+1], out[p.Ith(j)])
+		}
+	})
+}
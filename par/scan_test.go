@@ -0,0 +1,76 @@
+package par
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func ExampleScan() {
+	fmt.Println(Scan([]int{3, 1, 4, 1, 5, 9, 2, 6}, func(a, b int) int {
+		return a + b
+	}, WithGrain(3)))
+	// Output: [3 4 8 9 14 23 25 31]
+}
+
+func ExampleExclusiveScan() {
+	// Bucket offsets from bucket sizes.
+	sizes := []int{3, 0, 2, 5}
+	fmt.Println(ExclusiveScan(sizes, 0, func(a, b int) int {
+		return a + b
+	}))
+	// Output: [0 3 3 5]
+}
+
+// seqScan returns the inclusive prefix scan of in sequentially.
+func seqScan[T any](in []T, op func(T, T) T) []T {
+	out := make([]T, len(in))
+	for i, v := range in {
+		if i == 0 {
+			out[i] = v
+		} else {
+			out[i] = op(out[i-1], v)
+		}
+	}
+	return out
+}
+
+func concat(a, b string) string {
+	return a + b
+}
+
+func TestScan(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+	properties.Property("Scan should match the sequential scan", prop.ForAll(
+		func(in []string, grain, limit int) bool {
+			got := Scan(in, concat, WithGrain(grain), WithLimit(limit))
+			return slices.Equal(got, seqScan(in, concat))
+		},
+		gen.SliceOf(gen.AlphaString()),
+		gen.IntRange(0, 10),
+		gen.IntRange(0, 10),
+	))
+	properties.TestingRun(t)
+}
+
+func TestExclusiveScan(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+	properties.Property("ExclusiveScan should be the shifted inclusive scan", prop.ForAll(
+		func(in []int, grain int) bool {
+			add := func(a, b int) int { return a + b }
+			got := ExclusiveScan(in, 0, add, WithGrain(grain))
+			if len(in) == 0 {
+				return len(got) == 0
+			}
+			want := append([]int{0}, seqScan(in, add)[:len(in)-1]...)
+			return slices.Equal(got, want)
+		},
+		gen.SliceOf(gen.IntRange(-1000, 1000)),
+		gen.IntRange(0, 10),
+	))
+	properties.TestingRun(t)
+}
@@ -0,0 +1,112 @@
+package par
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Schedule decides how the indices are assigned to a fixed number of
+// goroutines, like the schedule clause of OpenMP. Use Static, Dynamic or Guided
+// to create one.
+type Schedule struct {
+	kind  scheduleKind
+	chunk int
+}
+
+type scheduleKind int
+
+const (
+	static scheduleKind = iota
+	dynamic
+	guided
+)
+
+// Static returns a schedule which splits the indices into equal contiguous
+// chunks, one for each goroutine. It has the least overhead, but a slow chunk
+// stalls the whole loop.
+func Static() Schedule {
+	return Schedule{kind: static}
+}
+
+// Dynamic returns a schedule where each goroutine takes the next chunk of the
+// given size as soon as it finishes the previous chunk. It balances the load
+// when the cost per index varies. If chunk is not positive, the grain set by
+// WithGrain or 1 is used.
+func Dynamic(chunk int) Schedule {
+	return Schedule{kind: dynamic, chunk: chunk}
+}
+
+// Guided returns a schedule similar to Dynamic, but the chunk size starts large
+// and decreases proportionally to the remaining indices, down to minChunk. It
+// has less overhead than Dynamic with a small chunk size. If minChunk is not
+// positive, the grain set by WithGrain or 1 is used.
+func Guided(minChunk int) Schedule {
+	return Schedule{kind: guided, chunk: minChunk}
+}
+
+// WithSchedule sets the schedule of the indices. A fixed number of goroutines,
+// limited by WithLimit or runtime.GOMAXPROCS(0) by default, process the
+// indices in chunks assigned by the schedule. The functions splitting a range
+// into chunks, like ForRange, schedule each number of the range instead, so
+// that a task of WithRate and WithObserver is a number.
+func WithSchedule(s Schedule) Option {
+	return func(c *config) {
+		c.schedule = &s
+	}
+}
+
+// workers returns the number of goroutines for the schedule.
+func (c *config) workers(n int) int {
+	if c.limit > 0 {
+		return min(c.limit, n)
+	}
+	return min(runtime.GOMAXPROCS(0), n)
+}
+
+// chunker returns a function returning the next chunk of indices [lo, hi) for
+// the worker w, or false if there is no more chunk. The function for a worker
+// must be called from a single goroutine.
+func (s Schedule) chunker(n, workers, grain int) func(w int) (lo, hi int, ok bool) {
+	chunk := s.chunk
+	if chunk <= 0 {
+		chunk = max(grain, 1)
+	}
+	switch s.kind {
+	case dynamic:
+		var next atomic.Int64
+		return func(int) (int, int, bool) {
+			lo := int(next.Add(int64(chunk)) - int64(chunk))
+			if lo >= n {
+				return 0, 0, false
+			}
+			return lo, min(lo+chunk, n), true
+		}
+	case guided:
+		var next atomic.Int64
+		return func(int) (int, int, bool) {
+			for {
+				lo := int(next.Load())
+				if lo >= n {
+					return 0, 0, false
+				}
+				size := max((n-lo+workers-1)/workers, chunk)
+				hi := min(lo+size, n)
+				if next.CompareAndSwap(int64(lo), int64(hi)) {
+					return lo, hi, true
+				}
+			}
+		}
+	default:
+		size := (n + workers - 1) / workers
+		taken := make([]bool, workers)
+		return func(w int) (int, int, bool) {
+			lo := min(w*size, n)
+			hi := min(lo+size, n)
+			if taken[w] || lo == hi {
+				return 0, 0, false
+			}
+			taken[w] = true
+			return lo, hi, true
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package par
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+func ExampleDynamic() {
+	// The cost per index is skewed. The dynamic schedule lets idle
+	// goroutines take the remaining chunks.
+	costs := []time.Duration{8, 8, 8, 8, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	var total atomic.Int64
+	For(len(costs), func(i int) {
+		time.Sleep(costs[i] * time.Millisecond)
+		total.Add(int64(costs[i]))
+	}, WithLimit(4), WithSchedule(Dynamic(2)))
+	fmt.Println(total.Load())
+	// Output: 44
+}
+
+func TestWithSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		n    int
+		opts []Option
+	}{
+		{"static", 1000, []Option{WithSchedule(Static()), WithLimit(3)}},
+		{"static small", 2, []Option{WithSchedule(Static()), WithLimit(3)}},
+		{"dynamic", 1000, []Option{WithSchedule(Dynamic(7)), WithLimit(3)}},
+		{"dynamic grain", 1000, []Option{WithSchedule(Dynamic(0)), WithGrain(13)}},
+		{"guided", 1000, []Option{WithSchedule(Guided(5)), WithLimit(4)}},
+		{"guided default", 1, []Option{WithSchedule(Guided(0))}},
+		{"sequential", 100, []Option{WithSchedule(Guided(1)), WithExecutor(Sequential())}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			visited := make([]atomic.Int32, tc.n)
+			For(tc.n, func(i int) {
+				visited[i].Add(1)
+			}, tc.opts...)
+			checkVisited(t, "", visited, once)
+		})
+	}
+}
+
+func TestWithSchedule_forRange(t *testing.T) {
+	fi := ranger.Range(999, -1, -2)
+	visited := make([]atomic.Int32, 1000)
+	ForRange(fi, func(v int) {
+		visited[v].Add(1)
+	}, WithSchedule(Guided(3)), WithLimit(4))
+	checkVisited(t, "", visited, func(i int) int32 { return int32(i % 2) })
+}
+
+func TestWithSchedule_panic(t *testing.T) {
+	defer expectPanic(t, "three")
+	For(100, panicAtThree, WithSchedule(Dynamic(2)), WithLimit(2))
+}
+
+// spin busy-waits for about n units of work.
+func spin(n int) int {
+	var x int
+	for i := 0; i < n*1000; i++ {
+		x += i ^ x
+	}
+	return x
+}
+
+// benchmarkSkewed runs a loop where the first 1/8 of the indices cost 30 times
+// more than the others. It reports the maximum and the 99th percentile of the
+// loop latency in addition to the mean, as the schedules differ in how long the
+// slowest worker takes. It is skipped on a single CPU where the schedules
+// cannot make a difference.
+func benchmarkSkewed(b *testing.B, opts ...Option) {
+	if runtime.NumCPU() < 2 {
+		b.Skip("skewed loops need more than one CPU")
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(min(runtime.NumCPU(), 8)))
+	const n = 4096
+	var sink atomic.Int64
+	latencies := make([]time.Duration, 0, b.N)
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		ForRange(ranger.Range(0, n, 1), func(v int) {
+			cost := 1
+			if v < n/8 {
+				cost = 30
+			}
+			sink.Add(int64(spin(cost)))
+		}, append([]Option{WithLimit(8)}, opts...)...)
+		latencies = append(latencies, time.Since(start))
+	}
+	slices.Sort(latencies)
+	b.ReportMetric(float64(latencies[len(latencies)-1].Nanoseconds()), "max-ns/op")
+	b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns/op")
+}
+
+func BenchmarkSkewed_default(b *testing.B) {
+	benchmarkSkewed(b)
+}
+
+func BenchmarkSkewed_static(b *testing.B) {
+	benchmarkSkewed(b, WithSchedule(Static()))
+}
+
+func BenchmarkSkewed_dynamic(b *testing.B) {
+	benchmarkSkewed(b, WithSchedule(Dynamic(16)))
+}
+
+func BenchmarkSkewed_guided(b *testing.B) {
+	benchmarkSkewed(b, WithSchedule(Guided(16)))
+}
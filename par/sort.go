@@ -0,0 +1,291 @@
+package par
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// sortGrain is the default number of elements sorted sequentially.
+const sortGrain = 2048
+
+// sorter sorts data concurrently with the config.
+type sorter struct {
+	c     *config
+	data  sort.Interface
+	grain int
+	// tokens bounds the extra goroutines across the recursion if WithLimit is
+	// given without WithExecutor. It is nil otherwise.
+	tokens chan struct{}
+}
+
+func newSorter(data sort.Interface, opts []Option) *sorter {
+	c := newConfig(opts)
+	grain := c.grain
+	if grain <= 0 {
+		grain = sortGrain
+	}
+	s := &sorter{c: c, data: data, grain: grain}
+	if c.limit > 0 && c.executor == nil {
+		s.tokens = make(chan struct{}, c.limit-1)
+	}
+	return s
+}
+
+// run calls f(i) for i from 0 to n-1 concurrently as config.run does. With the
+// tokens, only as many goroutines as the tokens taken without blocking run in
+// addition to the calling goroutine, and the calls run sequentially if no token
+// is left.
+func (s *sorter) run(n int, f func(i int)) {
+	if s.tokens == nil {
+		s.c.run(n, f)
+		return
+	}
+	c := *s.c
+	var taken int
+	for taken < n-1 && s.tryAcquire() {
+		taken++
+	}
+	defer func() {
+		for ; taken > 0; taken-- {
+			<-s.tokens
+		}
+	}()
+	if taken == 0 {
+		c.executor = Sequential()
+	} else {
+		c.limit = taken + 1
+	}
+	c.run(n, f)
+}
+
+func (s *sorter) tryAcquire() bool {
+	select {
+	case s.tokens <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// view returns the sort.Interface of the elements from lo to hi-1.
+func (s *sorter) view(lo, hi int) sort.Interface {
+	return subrange{s.data, lo, hi - lo}
+}
+
+// subrange is a contiguous view of sort.Interface. It is the same as the view
+// by ranger.Range(lo, hi, 1).AsSortInterface(data), but faster because it does
+// not check the bounds in every call of Less and Swap.
+type subrange struct {
+	data sort.Interface
+	lo   int
+	n    int
+}
+
+func (r subrange) Len() int           { return r.n }
+func (r subrange) Less(i, j int) bool { return r.data.Less(r.lo+i, r.lo+j) }
+func (r subrange) Swap(i, j int)      { r.data.Swap(r.lo+i, r.lo+j) }
+
+// Sort sorts data concurrently. It is not stable. The data must allow Less and
+// Swap called concurrently for disjoint indices, which is true for slices. The
+// range is split by quicksort until the parts are smaller than the grain set by
+// WithGrain, then the parts are sorted by sort.Sort. To sort a view of data
+// given by ranger.FiniteIth, pass the result of AsSortInterface. WithLimit
+// bounds the goroutines sorting at the same time across the recursion.
+func Sort(data sort.Interface, opts ...Option) {
+	s := newSorter(data, opts)
+	n := data.Len()
+	s.quickSort(0, n, 2*bits.Len(uint(n)))
+}
+
+// quickSort sorts the elements from lo to hi-1. If depth reaches zero, the
+// remaining elements are sorted by sort.Sort to avoid the quadratic worst case.
+func (s *sorter) quickSort(lo, hi, depth int) {
+	if hi-lo <= s.grain || depth == 0 {
+		sort.Sort(s.view(lo, hi))
+		return
+	}
+	m := s.partition(lo, hi)
+	s.run(2, func(i int) {
+		if i == 0 {
+			s.quickSort(lo, m, depth-1)
+		} else {
+			s.quickSort(m+1, hi, depth-1)
+		}
+	})
+}
+
+// partition partitions the elements from lo to hi-1 by the median of three
+// pivot, and returns the index of the pivot. Elements equal to the pivot may be
+// on either side.
+func (s *sorter) partition(lo, hi int) int {
+	data := s.data
+	a, b, c := lo, lo+(hi-lo)/2, hi-1
+	if data.Less(b, a) {
+		data.Swap(a, b)
+	}
+	if data.Less(c, b) {
+		data.Swap(b, c)
+		if data.Less(b, a) {
+			data.Swap(a, b)
+		}
+	}
+	// Move the median to lo.
+	data.Swap(a, b)
+	i, j := lo+1, hi-1
+	for {
+		for i <= j && data.Less(i, lo) {
+			i++
+		}
+		for i <= j && data.Less(lo, j) {
+			j--
+		}
+		if i >= j {
+			break
+		}
+		data.Swap(i, j)
+		i++
+		j--
+	}
+	data.Swap(lo, j)
+	return j
+}
+
+// Stable sorts data concurrently keeping the original order of equal elements.
+// The data must allow Less and Swap called concurrently for disjoint indices,
+// which is true for slices. The range is split into the parts of the grain set
+// by WithGrain, or as many parts as the goroutines. The parts are sorted by
+// sort.Stable, and then merged in place concurrently. WithLimit bounds the
+// goroutines sorting at the same time across the recursion.
+func Stable(data sort.Interface, opts ...Option) {
+	s := newSorter(data, opts)
+	n := data.Len()
+	s.c.defaultLimit()
+	parts := s.c.chunks(n)
+	if s.c.grain <= 0 {
+		parts = min(parts, (n+s.grain-1)/s.grain)
+	}
+	if parts <= 1 {
+		sort.Stable(data)
+		return
+	}
+	partSize := (n + parts - 1) / parts
+	s.run(parts, func(i int) {
+		lo := min(i*partSize, n)
+		sort.Stable(s.view(lo, min(lo+partSize, n)))
+	})
+	for width := partSize; width < n; width *= 2 {
+		s.run((n+2*width-1)/(2*width), func(i int) {
+			lo := 2 * i * width
+			m := min(lo+width, n)
+			hi := min(lo+2*width, n)
+			if m < hi {
+				s.symMerge(lo, m, hi)
+			}
+		})
+	}
+}
+
+// symMerge merges the sorted elements [a, m) and [m, b) in place, by the
+// SymMerge algorithm of Pok-Son Kim and Arne Kutzner as in the standard sort
+// package. The two recursive merges on disjoint ranges run concurrently if the
+// range is larger than the grain.
+func (s *sorter) symMerge(a, m, b int) {
+	data := s.data
+	if m-a == 1 {
+		// Insert data[a] into [m, b) by binary search.
+		i, j := m, b
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if data.Less(h, a) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := a; k < i-1; k++ {
+			data.Swap(k, k+1)
+		}
+		return
+	}
+	if b-m == 1 {
+		// Insert data[m] into [a, m) by binary search.
+		i, j := a, m
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if !data.Less(m, h) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := m; k > i; k-- {
+			data.Swap(k, k-1)
+		}
+		return
+	}
+	mid := int(uint(a+b) >> 1)
+	n := mid + m
+	var start, r int
+	if m > mid {
+		start, r = n-b, mid
+	} else {
+		start, r = a, m
+	}
+	p := n - 1
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !data.Less(p-c, c) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+	end := n - start
+	if start < m && m < end {
+		rotate(data, start, m, end)
+	}
+	left := func() {
+		if a < start && start < mid {
+			s.symMerge(a, start, mid)
+		}
+	}
+	right := func() {
+		if mid < end && end < b {
+			s.symMerge(mid, end, b)
+		}
+	}
+	if b-a <= s.grain {
+		left()
+		right()
+		return
+	}
+	s.run(2, func(i int) {
+		if i == 0 {
+			left()
+		} else {
+			right()
+		}
+	})
+}
+
+// rotate rotates the two consecutive blocks [a, m) and [m, b) in place.
+func rotate(data sort.Interface, a, m, b int) {
+	i, j := m-a, b-m
+	for i != j {
+		if i > j {
+			swapRange(data, m-i, m, j)
+			i -= j
+		} else {
+			swapRange(data, m-i, m+j-i, i)
+			j -= i
+		}
+	}
+	swapRange(data, m-i, m, i)
+}
+
+// swapRange swaps the n elements from a with the n elements from b.
+func swapRange(data sort.Interface, a, b, n int) {
+	for i := 0; i < n; i++ {
+		data.Swap(a+i, b+i)
+	}
+}
@@ -0,0 +1,176 @@
+package par
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jaeyeom/sugo/ranger"
+)
+
+func ExampleSort() {
+	nums := sort.IntSlice{5, 2, 8, 1, 9, 3}
+	Sort(nums)
+	fmt.Println(nums)
+	// Output: [1 2 3 5 8 9]
+}
+
+func ExampleStable_view() {
+	// Sort the elements at the even indices only.
+	names := sort.StringSlice{"d", "x", "b", "y", "c", "z", "a"}
+	Stable(ranger.Range(0, len(names), 2).AsSortInterface(names))
+	fmt.Println(names)
+	// Output: [a x b y c z d]
+}
+
+// record is a key with the original position to check stability.
+type record struct {
+	key, pos int
+}
+
+type byKey []record
+
+func (s byKey) Len() int           { return len(s) }
+func (s byKey) Less(i, j int) bool { return s[i].key < s[j].key }
+func (s byKey) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func randomRecords(rnd *rand.Rand, n, keys int) byKey {
+	records := make(byKey, n)
+	for i := range records {
+		records[i] = record{rnd.IntN(keys), i}
+	}
+	return records
+}
+
+var sortCases = []struct {
+	name string
+	n    int
+	keys int
+	opts []Option
+}{
+	{"empty", 0, 1, nil},
+	{"single", 1, 1, nil},
+	{"small", 100, 10, nil},
+	{"large", 100000, 1000000, nil},
+	{"duplicates", 100000, 3, nil},
+	{"grain", 10000, 100, []Option{WithGrain(7)}},
+	{"limit", 10000, 100, []Option{WithGrain(100), WithLimit(3)}},
+	{"limit one", 10000, 100, []Option{WithGrain(100), WithLimit(1)}},
+	{"sequential", 10000, 100, []Option{WithGrain(100), WithExecutor(Sequential())}},
+}
+
+func TestSort(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	for _, tc := range sortCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records := randomRecords(rnd, tc.n, tc.keys)
+			Sort(records, tc.opts...)
+			if !sort.IsSorted(records) {
+				t.Error("not sorted")
+			}
+		})
+	}
+}
+
+func TestSort_sorted(t *testing.T) {
+	nums := make(sort.IntSlice, 100000)
+	for i := range nums {
+		nums[i] = i
+	}
+	Sort(sort.Reverse(nums))
+	if !sort.IsSorted(sort.Reverse(nums)) {
+		t.Error("not sorted")
+	}
+	Sort(nums, WithGrain(1))
+	if !sort.IsSorted(nums) {
+		t.Error("not sorted")
+	}
+}
+
+func TestStable(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	for _, tc := range sortCases {
+		t.Run(tc.name, func(t *testing.T) {
+			records := randomRecords(rnd, tc.n, tc.keys)
+			Stable(records, tc.opts...)
+			for i := 1; i < len(records); i++ {
+				prev, cur := records[i-1], records[i]
+				if prev.key > cur.key || prev.key == cur.key && prev.pos > cur.pos {
+					t.Fatalf("records[%d] = %v and records[%d] = %v are not in order", i-1, prev, i, cur)
+				}
+			}
+		})
+	}
+}
+
+// goroutineCounter is a sort.Interface recording the maximum number of
+// goroutines while sorting.
+type goroutineCounter struct {
+	byKey
+	max atomic.Int32
+}
+
+func (c *goroutineCounter) Less(i, j int) bool {
+	n := int32(runtime.NumGoroutine())
+	for {
+		m := c.max.Load()
+		if n <= m || c.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	return c.byKey.Less(i, j)
+}
+
+func TestSort_limit(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(7, 8))
+	for _, sortFunc := range []func(sort.Interface, ...Option){Sort, Stable} {
+		for _, limit := range []int{1, 2, 4} {
+			data := &goroutineCounter{byKey: randomRecords(rnd, 20000, 1000)}
+			base := runtime.NumGoroutine()
+			sortFunc(data, WithGrain(50), WithLimit(limit))
+			if !sort.IsSorted(data.byKey) {
+				t.Errorf("limit %d: not sorted", limit)
+			}
+			// Each extra goroutine may block its parent until its children
+			// finish, so at most twice as many goroutines exist.
+			if extra := int(data.max.Load()) - base; extra > 2*(limit-1) {
+				t.Errorf("limit %d: %d extra goroutines, want at most %d", limit, extra, 2*(limit-1))
+			}
+		}
+	}
+}
+
+func benchmarkSort(b *testing.B, sortFunc func(sort.Interface)) {
+	rnd := rand.New(rand.NewPCG(5, 6))
+	orig := make(sort.IntSlice, 1000000)
+	for i := range orig {
+		orig[i] = rnd.Int()
+	}
+	nums := make(sort.IntSlice, len(orig))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(nums, orig)
+		b.StartTimer()
+		sortFunc(nums)
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	benchmarkSort(b, func(data sort.Interface) { Sort(data) })
+}
+
+func BenchmarkStable(b *testing.B) {
+	benchmarkSort(b, func(data sort.Interface) { Stable(data) })
+}
+
+func BenchmarkSortSequential(b *testing.B) {
+	benchmarkSort(b, sort.Sort)
+}
+
+func BenchmarkStableSequential(b *testing.B) {
+	benchmarkSort(b, sort.Stable)
+}
@@ -143,7 +143,9 @@ func FromIndices(indices []int) FiniteIth {
 	}}
 }
 
-// Partition returns ith part out of numParts parts of the range.
+// Partition returns ith part out of numParts parts of the range. The trailing
+// parts may be empty because parts have ceil(Size/numParts) elements, for
+// example Range(0, 9, 1).Partition(3, 4) is empty.
 func (f FiniteIth) Partition(i, numParts int) FiniteIth {
 	if numParts <= 0 || i >= numParts {
 		panic("wrong arguments")
@@ -152,11 +154,9 @@ func (f FiniteIth) Partition(i, numParts int) FiniteIth {
 		return f
 	}
 	partSize := (f.Size + numParts - 1) / numParts
-	begin := partSize * i
-	if i == numParts-1 {
-		partSize = f.Size - begin
-	}
-	return FiniteIth{Size: partSize, Ith: func(i int) int {
+	begin := min(partSize*i, f.Size)
+	end := min(begin+partSize, f.Size)
+	return FiniteIth{Size: end - begin, Ith: func(i int) int {
 		return f.Ith(begin + i)
 	}}
 }
@@ -198,6 +198,29 @@ func ExampleFiniteIth_Partition() {
 	// [18 19 20 21]
 }
 
+func TestFiniteIth_Partition(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+	properties.Property("partitions should cover the range in order", prop.ForAll(
+		func(size, numParts int) bool {
+			fi := Range(0, size, 1)
+			var next int
+			for i := 0; i < numParts; i++ {
+				p := fi.Partition(i, numParts)
+				for j := 0; j < p.Size; j++ {
+					if p.Ith(j) != next {
+						return false
+					}
+					next++
+				}
+			}
+			return next == size
+		},
+		gen.IntRange(0, 1000),
+		gen.IntRange(1, 100),
+	))
+	properties.TestingRun(t)
+}
+
 func ExampleFiniteIth_AsSortInterface() {
 	s := NDShape(ND{3, 3})
 	alphas := sort.StringSlice{
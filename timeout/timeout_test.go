@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/jaeyeom/sugo/leaktest"
 )
 
 func TestDoWithTimeout_Success(t *testing.T) {
+	leaktest.Check(t)
 	ctx := context.Background()
 	timeout := 100 * time.Millisecond
 	f := func() error {
@@ -23,6 +26,7 @@ func TestDoWithTimeout_Success(t *testing.T) {
 }
 
 func TestDoWithTimeout_Timeout(t *testing.T) {
+	leaktest.Check(t)
 	ctx := context.Background()
 	timeout := 10 * time.Millisecond
 	f := func() error {
@@ -37,6 +41,7 @@ func TestDoWithTimeout_Timeout(t *testing.T) {
 }
 
 func TestDoWithTimeout_ContextCanceled(t *testing.T) {
+	leaktest.Check(t)
 	ctx, cancel := context.WithCancel(context.Background())
 	timeout := 100 * time.Millisecond
 	f := func() error {
@@ -85,6 +90,7 @@ func ExampleDoWithTimeout() {
 }
 
 func TestDoWithTimeout_FunctionError(t *testing.T) {
+	leaktest.Check(t)
 	ctx := context.Background()
 	timeout := 100 * time.Millisecond
 	expectedErr := errors.New("function error")
@@ -99,6 +105,7 @@ func TestDoWithTimeout_FunctionError(t *testing.T) {
 }
 
 func TestDoWithTimeout_ContextCanceledDuringExecution(t *testing.T) {
+	leaktest.Check(t)
 	ctx, cancel := context.WithCancel(context.Background())
 	timeout := 100 * time.Millisecond
 	f := func() error {